@@ -0,0 +1,167 @@
+package urlvalues
+
+import (
+	"encoding"
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Reasons for rejecting value.
+const (
+	reasonSyntax   = "syntax"
+	reasonOverflow = "overflow"
+	reasonSign     = "sign"
+	reasonUTF8     = "utf8"
+	reasonControl  = "control"
+)
+
+// checkValue returns reason why form.Decoder will fail to convert value
+// to typ or empty string if value is ok.
+func checkValue(typ reflect.Type, value string) (reason string) {
+	var err error
+	switch typ.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if value == "" {
+			return ""
+		}
+		_, err = strconv.ParseInt(value, 10, typ.Bits())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if value == "" {
+			return ""
+		}
+		_, err = strconv.ParseUint(value, 10, typ.Bits())
+		if err != nil && strings.HasPrefix(value, "-") {
+			if _, err := strconv.ParseInt(value, 10, 64); err == nil || errors.Is(err, strconv.ErrRange) {
+				return reasonSign
+			}
+		}
+	case reflect.Float32, reflect.Float64:
+		if value == "" {
+			return ""
+		}
+		_, err = strconv.ParseFloat(value, typ.Bits())
+	case reflect.Bool:
+		switch value {
+		case "", "1", "t", "T", "true", "TRUE", "True", "on", "yes", "ok":
+		case "0", "f", "F", "false", "FALSE", "False", "off", "no":
+		default:
+			return reasonSyntax
+		}
+	}
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, strconv.ErrRange):
+		return reasonOverflow
+	default:
+		return reasonSyntax
+	}
+}
+
+// checkNotEmpty returns reason why empty value should be rejected for typ
+// when empty values are handled like missing but can't be dropped (inside
+// list) or empty string if it's ok.
+func checkNotEmpty(typ reflect.Type) (reason string) {
+	switch typ.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64,
+		reflect.Bool:
+		return reasonSyntax
+	default:
+		return ""
+	}
+}
+
+// checkText returns reason why value is not a valid text or empty string
+// if it's ok. Control characters listed in allowed are ok.
+func checkText(value, allowed string) (reason string) {
+	if !utf8.ValidString(value) {
+		return reasonUTF8
+	}
+	for _, r := range value {
+		if unicode.IsControl(r) && !strings.ContainsRune(allowed, r) {
+			return reasonControl
+		}
+	}
+	return ""
+}
+
+// Value normalizations.
+const (
+	normTrim = 1 << iota
+	normCollapse
+	normLower
+	normUpper
+)
+
+// normalize returns value with applied normalizations from norm.
+func normalize(norm int, value string) string {
+	switch {
+	case norm&normCollapse != 0:
+		value = strings.Join(strings.Fields(value), " ")
+	case norm&normTrim != 0:
+		value = strings.TrimSpace(value)
+	}
+	switch {
+	case norm&normLower != 0:
+		value = strings.ToLower(value)
+	case norm&normUpper != 0:
+		value = strings.ToUpper(value)
+	}
+	return value
+}
+
+//nolint:gochecknoglobals
+var typTextUnmarshaler = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// isTextKey returns true if map key of type typ should be decoded using
+// encoding.TextUnmarshaler.
+func isTextKey(typ reflect.Type) bool {
+	switch typ.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return false
+	default:
+		return reflect.PtrTo(typ).Implements(typTextUnmarshaler)
+	}
+}
+
+// checkKey returns reason why form.Decoder will fail to convert key to
+// map key of type typ or empty string if key is ok.
+func checkKey(typ reflect.Type, key string) (reason string) {
+	if isTextKey(typ) {
+		err := reflect.New(typ).Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(key))
+		if err != nil {
+			return reasonSyntax
+		}
+		return ""
+	}
+	return checkValue(typ, key)
+}
+
+// valueBits returns bit size of typ or 0 if it's not a number.
+func valueBits(typ reflect.Type) int {
+	switch typ.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return typ.Bits()
+	default:
+		return 0
+	}
+}
+
+// kindName returns name of sized type for kind, like "int64" for int.
+func kindName(kind reflect.Kind, bits int) string {
+	if bits == 0 {
+		return kind.String()
+	}
+	return strings.TrimRight(kind.String(), "0123456789") + strconv.Itoa(bits)
+}
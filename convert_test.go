@@ -0,0 +1,110 @@
+package urlvalues
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/powerman/check"
+)
+
+func TestCheckValue(tt *testing.T) {
+	t := check.T(tt)
+	cases := []struct {
+		v      interface{}
+		value  string
+		reason string
+	}{
+		{int(0), "", ""},
+		{int(0), "-9223372036854775808", ""},
+		{int(0), "9223372036854775808", "overflow"},
+		{int(0), "0x10", "syntax"},
+		{int16(0), "-32769", "overflow"},
+		{uint(0), "18446744073709551615", ""},
+		{uint(0), "18446744073709551616", "overflow"},
+		{uint8(0), "-0", "sign"},
+		{uint8(0), "-99999999999999999999", "sign"},
+		{uint8(0), "-x", "syntax"},
+		{uint8(0), "256", "overflow"},
+		{float32(0), "3.4e38", ""},
+		{float32(0), "3.5e38", "overflow"},
+		{float64(0), "1,5", "syntax"},
+		{false, "", ""},
+		{false, "yes", ""},
+		{false, "Yes", "syntax"},
+		{"", "\x00", ""},
+	}
+	for _, v := range cases {
+		t.Equal(checkValue(reflect.TypeOf(v.v), v.value), v.reason, "%T %q", v.v, v.value)
+	}
+}
+
+func TestCheckKey(tt *testing.T) {
+	t := check.T(tt)
+	t.Equal(checkKey(reflect.TypeOf(""), ""), "")
+	t.Equal(checkKey(reflect.TypeOf(0), "x"), "syntax")
+	t.Equal(checkKey(reflect.TypeOf(uint(0)), "-1"), "sign")
+	t.Equal(checkKey(reflect.TypeOf(textKey{}), "a:b"), "")
+	t.Equal(checkKey(reflect.TypeOf(textKey{}), "a"), "syntax")
+	t.False(isTextKey(reflect.TypeOf(0)))
+	t.True(isTextKey(reflect.TypeOf(textKey{})))
+	t.False(isTextKey(reflect.TypeOf(struct{}{})))
+}
+
+func TestCheckNotEmpty(tt *testing.T) {
+	t := check.T(tt)
+	t.Equal(checkNotEmpty(reflect.TypeOf(0)), reasonSyntax)
+	t.Equal(checkNotEmpty(reflect.TypeOf(0.0)), reasonSyntax)
+	t.Equal(checkNotEmpty(reflect.TypeOf(false)), reasonSyntax)
+	t.Equal(checkNotEmpty(reflect.TypeOf("")), "")
+	t.Equal(checkNotEmpty(reflect.TypeOf(textKey{})), "")
+}
+
+func TestCheckText(tt *testing.T) {
+	t := check.T(tt)
+	tests := []struct {
+		value   string
+		allowed string
+		want    string
+	}{
+		{"", "", ""},
+		{"Привіт, world!", "", ""},
+		{"a\tb", "", reasonControl},
+		{"a\tb", "\t", ""},
+		{"a\x00b", "\t", reasonControl},
+		{"a\x7fb", "", reasonControl},
+		{"a\u0085b", "", reasonControl},
+		{"a\xc0b", "", reasonUTF8},
+		{"a\xed\xa0\x80b", "", reasonUTF8},
+	}
+	for _, tc := range tests {
+		t.Equal(checkText(tc.value, tc.allowed), tc.want, tc.value)
+	}
+}
+
+func TestNormalizeValue(tt *testing.T) {
+	t := check.T(tt)
+	tests := []struct {
+		norm  int
+		value string
+		want  string
+	}{
+		{0, " a  B ", " a  B "},
+		{normTrim, " a  B ", "a  B"},
+		{normCollapse, " a \t\n B ", "a B"},
+		{normTrim | normCollapse, " a  B ", "a B"},
+		{normLower, " a  B ", " a  b "},
+		{normUpper, " a  B ", " A  B "},
+		{normTrim | normUpper, " a  B ", "A  B"},
+	}
+	for _, tc := range tests {
+		t.Equal(normalize(tc.norm, tc.value), tc.want, tc.norm, tc.value)
+	}
+}
+
+func TestKindName(tt *testing.T) {
+	t := check.T(tt)
+	t.Equal(kindName(reflect.Int, 64), "int64")
+	t.Equal(kindName(reflect.Uint8, 8), "uint8")
+	t.Equal(kindName(reflect.Float32, 32), "float32")
+	t.Equal(kindName(reflect.Bool, 0), "bool")
+}
@@ -0,0 +1,228 @@
+package urlvalues
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// Explanation describe how StrictDecoder handle one of url.Values keys.
+type Explanation struct {
+	Key         string   // Key in values, map keys in it may be redacted.
+	Pattern     string   // Matched pattern, "-" if key doesn't match any.
+	Field       string   // Path to Go field, like "Items[].Name".
+	Indices     []int    // Value of each [idx] in Key, -1 if it doesn't fit in int.
+	MapKeys     []string // Value of each [key] in Key, may be redacted.
+	Rule        string   // One of "accepted", "rejected", "remain", "ignored" or "unknown".
+	Reasons     []string // Codes of errors and warnings related to Key.
+	Suggestions []string // Patterns which may be meant instead of unknown Key.
+}
+
+// Explanations is a result of Explain.
+type Explanations []Explanation
+
+// String returns explanations formatted as a table.
+func (list Explanations) String() string {
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "KEY\tPATTERN\tFIELD\tINDICES\tMAP KEYS\tRULE\tREASONS")
+	for _, e := range list {
+		indices := make([]string, len(e.Indices))
+		for i, index := range e.Indices {
+			indices[i] = strconv.Itoa(index)
+		}
+		mapKeys := make([]string, len(e.MapKeys))
+		for i, key := range e.MapKeys {
+			mapKeys[i] = strconv.Quote(key)
+		}
+		reasons := strings.Join(e.Reasons, ", ")
+		if len(e.Suggestions) > 0 {
+			suggestions := make([]string, len(e.Suggestions))
+			for i, s := range e.Suggestions {
+				suggestions[i] = strconv.Quote(s)
+			}
+			reasons = "did you mean " + strings.Join(suggestions, " or ") + "?"
+		}
+		_, _ = fmt.Fprintf(w, "%q\t%s\t%s\t%s\t%s\t%s\t%s\n", e.Key, e.Pattern, e.Field,
+			strings.Join(indices, ","), strings.Join(mapKeys, ","), e.Rule, reasons)
+	}
+	_ = w.Flush()
+	lines := strings.Split(b.String(), "\n")
+	for i := range lines {
+		lines[i] = strings.TrimRight(lines[i], " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Explain returns explanation (sorted by key) of how each key in values
+// will be handled by Decode to v.
+//
+// Param v can be a struct, a pointer to a struct or reflect.Type of a struct.
+//
+// Values are not decoded, so errors added by Validator and
+// URLValuesValidator are not included.
+func (d *StrictDecoder) Explain(v interface{}, values url.Values) Explanations {
+	typ := structType(v)
+	params := paramsForStruct(d.decoderOpts, typ)
+	res := d.validate(typ, values)
+	errs := res.errs
+	d.ignore(&errs, &res.warns)
+	var shapes map[string]string
+	if d.keyMatching != nil {
+		shapes = d.keyShapes(typ, params)
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	list := make(Explanations, 0, len(keys))
+	for _, key := range keys {
+		name := key
+		if shapes != nil {
+			name, _ = d.matchKey(shapes, key)
+		}
+		e := Explanation{Key: key, Pattern: "-", Rule: "unknown"}
+		if err := findKey(errs.details["-"], name); err != nil {
+			if err.Reason != "" {
+				e.Rule, e.Reasons = "rejected", []string{"invalid text"}
+			}
+			e.Suggestions = err.Suggestions
+		} else if findKey(res.warns.details["-"], name) != nil {
+			e.Rule = "ignored"
+		} else if capture, ok := res.remain[name]; ok {
+			e.Pattern, e.Field, e.Rule = capture.c.alias, fieldPath(typ, capture.c.index), "remain"
+		} else {
+			d.explainKey(&e, typ, params, errs, res.warns, name)
+		}
+		list = append(list, e)
+	}
+	return list
+}
+
+// explainKey fills e for key matching one of params.
+func (d *StrictDecoder) explainKey(e *Explanation, typ reflect.Type, params map[string]*constraint, errs, warns Errs, key string) {
+	c, errKey := params[key], key
+	if c != nil && !c.remain && !strings.ContainsRune(key, '[') {
+		e.Pattern = key
+	} else {
+		c = nil
+		for pattern, pc := range params {
+			if pc.remain || !strings.ContainsRune(pattern, '[') {
+				continue
+			}
+			re := compilePattern(pattern)
+			match := re.FindStringSubmatch(key)
+			if match == nil {
+				continue
+			}
+			c, e.Pattern = pc, pattern
+			errKey = d.redactKey(pattern, c, key, re.SubexpNames(), re.FindStringSubmatchIndex(key))
+			for i, group := range re.SubexpNames() {
+				switch group {
+				case "idx":
+					index, err := strconv.Atoi(match[i])
+					if err != nil {
+						index = -1
+					}
+					e.Indices = append(e.Indices, index)
+				case "key":
+					e.MapKeys = append(e.MapKeys, d.redactValue(pattern, c, match[i]))
+				}
+			}
+			break
+		}
+	}
+	if c == nil {
+		return
+	}
+
+	if errKey != key {
+		e.Key = errKey
+	}
+	e.Field = fieldPath(typ, c.index)
+	e.Rule = "accepted"
+	e.Reasons = relatedCodes(errs, e.Pattern, c, errKey)
+	if len(e.Reasons) > 0 {
+		e.Rule = "rejected"
+	}
+	e.Reasons = append(e.Reasons, relatedCodes(warns, e.Pattern, c, errKey)...)
+}
+
+// findKey returns error for key or nil.
+func findKey(details []*FieldError, key string) *FieldError {
+	for _, err := range details {
+		if err.Key == key {
+			return err
+		}
+	}
+	return nil
+}
+
+// relatedCodes returns sorted codes from errs related to key matching
+// pattern described by c: errors with details for this key and errors
+// without key for pattern, it's alias or pattern up to some [key].
+func relatedCodes(errs Errs, pattern string, c *constraint, key string) (codes []string) {
+	seen := make(map[string]bool)
+	add := func(code string) {
+		if !seen[code] {
+			seen[code] = true
+			codes = append(codes, code)
+		}
+	}
+	for p, pcodes := range errs.Values {
+		if p == "-" {
+			continue
+		}
+		left := append([]string(nil), pcodes...)
+		for _, err := range errs.details[p] {
+			if err.Key == "" {
+				continue
+			}
+			for i := range left {
+				if left[i] == err.Code {
+					left = append(left[:i], left[i+1:]...)
+					break
+				}
+			}
+			if err.Key == key {
+				add(err.Code)
+			}
+		}
+		if p == pattern || p == c.alias || strings.HasSuffix(p, "]") && strings.HasPrefix(pattern, p) {
+			for _, code := range left {
+				add(code)
+			}
+		}
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// fieldPath returns path to Go field in typ with given index (which
+// contains -1 for elements of array/slice/map).
+func fieldPath(typ reflect.Type, index []int) string {
+	var b strings.Builder
+	for _, i := range index {
+		for typ.Kind() == reflect.Ptr {
+			typ = typ.Elem()
+		}
+		if i == -1 {
+			_, _ = b.WriteString("[]")
+			typ = typ.Elem()
+			continue
+		}
+		field := typ.Field(i)
+		if b.Len() > 0 {
+			_ = b.WriteByte('.')
+		}
+		_, _ = b.WriteString(field.Name)
+		typ = field.Type
+	}
+	return b.String()
+}
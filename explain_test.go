@@ -0,0 +1,76 @@
+package urlvalues
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/powerman/check"
+)
+
+func TestExplain(tt *testing.T) {
+	t := check.T(tt)
+	type Item struct {
+		Name string `form:"name"`
+	}
+	type Data struct {
+		Query string         `form:"q,deprecated=query"`
+		Items []Item         `form:"item"`
+		M     map[string]int `form:"m,keys=a|b"`
+		I     int
+		Ext   struct {
+			R url.Values `form:",remain"`
+		} `form:"ext"`
+	}
+	d := NewStrictDecoder()
+	list := d.Explain(Data{}, url.Values{
+		"query":         {"x"},
+		"item[1].name":  {"a"},
+		"item[01].name": {"b"},
+		"m[c]":          {"1"},
+		"I":             {"1", "2"},
+		"ext.foo":       {"bar"},
+		"itm[0].name":   {"c"},
+	})
+	t.DeepEqual(list, Explanations{
+		{Key: "I", Pattern: "I", Field: "I", Rule: "rejected", Reasons: []string{"multiple values"}},
+		{Key: "ext.foo", Pattern: "ext.R", Field: "Ext.R", Rule: "remain"},
+		{Key: "item[01].name", Pattern: "item[idx].name", Field: "Items[].Name", Indices: []int{1}, Rule: "rejected", Reasons: []string{"multiple values", "non-canonical index"}},
+		{Key: "item[1].name", Pattern: "item[idx].name", Field: "Items[].Name", Indices: []int{1}, Rule: "accepted"},
+		{Key: "itm[0].name", Pattern: "-", Rule: "unknown", Suggestions: []string{"item[idx].name"}},
+		{Key: "m[c]", Pattern: "m[key]", Field: "M", MapKeys: []string{"c"}, Rule: "rejected", Reasons: []string{"key not allowed"}},
+		{Key: "query", Pattern: "query", Field: "Query", Rule: "accepted", Reasons: []string{"deprecated"}},
+	})
+	t.Equal(list.String(), `KEY              PATTERN         FIELD         INDICES  MAP KEYS  RULE      REASONS
+"I"              I               I                                rejected  multiple values
+"ext.foo"        ext.R           Ext.R                            remain
+"item[01].name"  item[idx].name  Items[].Name  1                  rejected  multiple values, non-canonical index
+"item[1].name"   item[idx].name  Items[].Name  1                  accepted
+"itm[0].name"    -                                                unknown   did you mean "item[idx].name"?
+"m[c]"           m[key]          M                      "c"       rejected  key not allowed
+"query"          query           Query                            accepted  deprecated
+`)
+
+	d = NewStrictDecoder(IgnoreUnknownMatching("utm_*"), RejectInvalidText(""),
+		KeyMatching(MatchCaseInsensitive))
+	list = d.Explain(&Data{}, url.Values{
+		"utm_id":  {"1"},
+		"a\x00":   {"1"},
+		"ITEM[0]": {"x"},
+		"Q":       {" "},
+	})
+	t.DeepEqual(list, Explanations{
+		{Key: "ITEM[0]", Pattern: "-", Rule: "unknown"},
+		{Key: "Q", Pattern: "q", Field: "Query", Rule: "accepted"},
+		{Key: "a\x00", Pattern: "-", Rule: "rejected", Reasons: []string{"invalid text"}},
+		{Key: "utm_id", Pattern: "-", Rule: "ignored"},
+	})
+
+	var secret struct {
+		Tokens map[string]int `form:"t,secret"`
+	}
+	list = NewStrictDecoder().Explain(secret, url.Values{"t[abc]": {"x"}})
+	t.DeepEqual(list, Explanations{
+		{Key: "t[[redacted]]", Pattern: "t[key]", Field: "Tokens", MapKeys: []string{"[redacted]"}, Rule: "rejected", Reasons: []string{"wrong type"}},
+	})
+	t.NotContains(list.String(), "abc")
+}
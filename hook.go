@@ -0,0 +1,206 @@
+package urlvalues
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/go-playground/form"
+)
+
+// Validator can be implemented by Decode param v or it nested structs to
+// add cross-field validation.
+//
+// Returned error is added to Errs using pattern of struct implementing
+// Validator ("." for Decode param v) unless it is Errs, in which case its
+// errors are added to Errs with patterns prefixed by pattern of struct
+// implementing Validator (errors with empty pattern are added using
+// pattern of struct implementing Validator or ".").
+type Validator interface {
+	Validate() error
+}
+
+// URLValuesValidator can be implemented by Decode param v or it nested
+// structs to add cross-field validation.
+//
+// Errors added to errs will be added to Errs returned by Decode with
+// patterns prefixed by pattern of struct implementing URLValuesValidator
+// (errors with empty pattern are added using pattern of struct
+// implementing URLValuesValidator or ".").
+type URLValuesValidator interface {
+	ValidateURLValues(errs *Errs)
+}
+
+// rootPattern is used in Errs for errors related to Decode param v.
+const rootPattern = "."
+
+//nolint:gochecknoglobals
+var (
+	typValidator          = reflect.TypeOf((*Validator)(nil)).Elem()
+	typURLValuesValidator = reflect.TypeOf((*URLValuesValidator)(nil)).Elem()
+)
+
+//nolint:gochecknoglobals
+var (
+	hooksCacheMu sync.RWMutex
+	hooksCache   = make(map[decoderOpts]map[reflect.Type]bool)
+)
+
+// hasHooks returns true if typ or any of nested types implements
+// Validator or URLValuesValidator.
+func hasHooks(opts decoderOpts, typ reflect.Type) bool {
+	cache := hooksCache
+	if opts.naming != nil {
+		cache = opts.naming.hooksCache
+	}
+	hooksCacheMu.RLock()
+	has, ok := cache[opts][typ]
+	hooksCacheMu.RUnlock()
+	if ok {
+		return has
+	}
+
+	has = findHooks(opts, typ)
+
+	hooksCacheMu.Lock()
+	if cache[opts] == nil {
+		cache[opts] = make(map[reflect.Type]bool)
+	}
+	cache[opts][typ] = has
+	hooksCacheMu.Unlock()
+	return has
+}
+
+func findHooks(opts decoderOpts, typ reflect.Type) bool {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	switch typ.Kind() {
+	case reflect.Array, reflect.Slice, reflect.Map:
+		return findHooks(opts, typ.Elem())
+	case reflect.Struct:
+		if typ == typTime {
+			return false
+		}
+		if isHook(reflect.PtrTo(typ)) {
+			return true
+		}
+		for i := 0; i < typ.NumField(); i++ {
+			field := typ.Field(i)
+			if hookField(opts, field) != "" && findHooks(opts, field.Type) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isHook(typ reflect.Type) bool {
+	return typ.Implements(typValidator) || typ.Implements(typURLValuesValidator)
+}
+
+// hookField returns name of field or empty string if field can't contain
+// hooks called by Decode.
+// Embedded struct fields has name "." because their fields are promoted.
+func hookField(opts decoderOpts, field reflect.StructField) string {
+	tag, _ := fieldTag(opts, field)
+	switch {
+	case tag == nil:
+		return ""
+	case opts.mode == form.ModeExplicit && len(tag) == 1 && tag[0] == "":
+		return ""
+	}
+	for _, opt := range tag[1:] {
+		if opt == "remain" {
+			return ""
+		}
+	}
+	if field.Anonymous && tag[0] == "" {
+		return "."
+	}
+	if field.PkgPath != "" { // not exported
+		return ""
+	}
+	if tag[0] != "" {
+		return tag[0]
+	}
+	return opts.fieldName(field.Name)
+}
+
+// callHooks calls Validator and URLValuesValidator implemented by v
+// and its nested structs.
+//
+// Parameter pattern is used internally for recursion only.
+func (d *StrictDecoder) callHooks(errs *Errs, v reflect.Value, pattern string) {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Array, reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			d.callHooks(errs, v.Index(i), pattern+"[idx]")
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			d.callHooks(errs, iter.Value(), pattern+"[key]")
+		}
+	case reflect.Struct:
+		if v.Type() == typTime {
+			return
+		}
+		if !v.CanAddr() { // map value
+			elem := reflect.New(v.Type()).Elem()
+			elem.Set(v)
+			v = elem
+		}
+		d.callStructHooks(errs, v, pattern, true)
+	}
+}
+
+func (d *StrictDecoder) callStructHooks(errs *Errs, v reflect.Value, pattern string, hook bool) {
+	if hook {
+		if h, ok := v.Addr().Interface().(URLValuesValidator); ok {
+			sub := newErrs()
+			h.ValidateURLValues(&sub)
+			errs.merge(pattern, sub)
+		}
+		if h, ok := v.Addr().Interface().(Validator); ok {
+			switch err := h.Validate().(type) {
+			case nil:
+			case Errs:
+				errs.merge(pattern, err)
+			default:
+				sub := newErrs()
+				sub.Add("", err.Error())
+				errs.merge(pattern, sub)
+			}
+		}
+	}
+
+	typ := v.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		name := hookField(d.decoderOpts, field)
+		if name == "" || !hasHooks(d.decoderOpts, field.Type) {
+			continue
+		}
+		fv := v.Field(i)
+		if name == "." { // hooks of embedded struct are promoted
+			for fv.Kind() == reflect.Ptr && !fv.IsNil() {
+				fv = fv.Elem()
+			}
+			if fv.Kind() == reflect.Struct {
+				promoted := isHook(reflect.PtrTo(typ))
+				d.callStructHooks(errs, fv, pattern, !promoted && fv.Addr().CanInterface())
+			}
+			continue
+		}
+		if pattern != "" {
+			name = pattern + "." + name
+		}
+		d.callHooks(errs, fv, name)
+	}
+}
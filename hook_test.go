@@ -0,0 +1,149 @@
+package urlvalues
+
+import (
+	"errors"
+	"net/url"
+	"sort"
+	"testing"
+
+	"github.com/powerman/check"
+)
+
+type hookRange struct {
+	From int
+	To   int
+}
+
+func (r hookRange) Validate() error {
+	if r.From > r.To {
+		errs := newErrs()
+		errs.Add("From", "must not be greater than To")
+		return errs
+	}
+	return nil
+}
+
+type hookItem struct {
+	Name string
+}
+
+func (i *hookItem) Validate() error {
+	if i.Name == "" {
+		return errors.New("name required")
+	}
+	return nil
+}
+
+type hookEmbedded struct {
+	E int
+}
+
+func (e *hookEmbedded) ValidateURLValues(errs *Errs) {
+	if e.E < 0 {
+		errs.Add("E", "must not be negative")
+	}
+}
+
+type hookData struct {
+	hookEmbedded
+	ID    int
+	Email string
+	Range hookRange
+	PR    *hookRange `form:"pr"`
+	Items []hookItem `form:"item"`
+	M     map[string]hookItem
+	Skip  hookRange `form:"-"`
+}
+
+func (d *hookData) ValidateURLValues(errs *Errs) {
+	d.hookEmbedded.ValidateURLValues(errs)
+	if d.ID == 0 && d.Email == "" {
+		errs.Add("ID", "either ID or Email required")
+	}
+}
+
+func TestHooks(tt *testing.T) {
+	t := check.T(tt)
+	var data hookData
+	d := NewStrictDecoder()
+	t.Nil(d.Decode(&data, url.Values{
+		"ID":         {"1"},
+		"Range.From": {"1"},
+		"Range.To":   {"2"},
+	}))
+	data = hookData{Skip: hookRange{From: 2, To: 1}}
+	errs := d.Decode(&data, url.Values{
+		"E":            {"-1"},
+		"Range.From":   {"3"},
+		"Range.To":     {"2"},
+		"pr.From":      {"3"},
+		"item[0].Name": {"one"},
+		"item[1].Name": {""},
+		"M[a].Name":    {""},
+	})
+	t.DeepEqual(errs, Errs{Values: url.Values{
+		"E":          {"must not be negative"},
+		"ID":         {"either ID or Email required"},
+		"Range.From": {"must not be greater than To"},
+		"pr.From":    {"must not be greater than To"},
+		"item[idx]":  {"name required"},
+		"M[key]":     {"name required"},
+	}})
+
+	var reported []Errs
+	d = NewStrictDecoder(ShadowMode(func(errs Errs) { reported = append(reported, errs) }))
+	data = hookData{}
+	t.Nil(d.Decode(&data, url.Values{"ID": {"1"}, "Range.From": {"3"}}))
+	if t.Len(reported, 1) {
+		t.DeepEqual(reported[0].Values, url.Values{
+			"Range.From": {"must not be greater than To"},
+		})
+	}
+	reported = nil
+	t.Nil(d.Decode(&data, url.Values{"ID": {"1", "2"}, "Range.From": {"3"}}))
+	if t.Len(reported, 1) {
+		t.DeepEqual(reported[0].Values, url.Values{
+			"ID":         {"multiple values"},
+			"Range.From": {"must not be greater than To"},
+		})
+	}
+}
+
+func TestHooksEmbedded(tt *testing.T) {
+	t := check.T(tt)
+	type Data struct {
+		*hookEmbedded
+		hookItem
+	}
+	data := Data{hookEmbedded: &hookEmbedded{}}
+	d := NewStrictDecoder()
+	errs := d.Decode(&data, url.Values{"E": {"-1"}})
+	t.DeepEqual(errs, Errs{Values: url.Values{
+		"E": {"must not be negative"},
+		".": {"name required"},
+	}})
+}
+
+func TestErrsMerge(tt *testing.T) {
+	t := check.T(tt)
+	other := newErrs()
+	other.Add("", "a")
+	other.Add("B", "b")
+	other.Add("[key]", "c")
+	other.addError(&FieldError{Pattern: "D", Code: "d"})
+	errs := newErrs()
+	errs.merge("P", other)
+	keys := make([]string, 0, len(errs.Values))
+	for key := range errs.Values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	t.DeepEqual(keys, []string{"P", "P.B", "P.D", "P[key]"})
+	t.DeepEqual(errs.Details("P.D"), []*FieldError{{Pattern: "P.D", Code: "d"}})
+	t.Equal(other.Details("D")[0].Pattern, "D")
+
+	errs = newErrs()
+	errs.merge("", other)
+	t.DeepEqual(errs.Values["."], []string{"a"})
+	t.Nil(errs.Values[""])
+}
@@ -0,0 +1,175 @@
+package urlvalues
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// KeyMatching return an option for NewStrictDecoder.
+//
+// With this option keys in values are matched to fields after normalizing
+// each name in key (part of key between dots outside of brackets) and
+// field's pattern using normalize. Matched keys are renamed to field's
+// pattern names before validation and decoding. If values contain several
+// variants of same key then "multiple names for same value" error will be
+// reported.
+//
+// Use nil (default) for exact matching, or one of MatchCaseInsensitive,
+// MatchIgnoreCaseAndSeparators, or custom function.
+//
+// It panics on decoding to struct with fields which names become same
+// after normalizing.
+func KeyMatching(normalize func(name string) string) StrictDecoderOption {
+	return StrictDecoderOption(func(d *StrictDecoder) {
+		d.keyMatching = normalize
+	})
+}
+
+// MatchCaseInsensitive can be used with KeyMatching to match keys like
+// "PageSize" and "pagesize".
+func MatchCaseInsensitive(name string) string {
+	return strings.ToLower(name)
+}
+
+// MatchIgnoreCaseAndSeparators can be used with KeyMatching to match keys
+// like "PageSize", "pagesize", "page_size" and "page-size".
+func MatchIgnoreCaseAndSeparators(name string) string {
+	return strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(name))
+}
+
+// splitKey splits key into names (outside of brackets, including dots)
+// and brackets (including brackets itself).
+func splitKey(key string) (parts []string) {
+	for key != "" {
+		var i int
+		if key[0] == '[' {
+			i = strings.IndexByte(key, ']') + 1
+		} else {
+			i = strings.IndexByte(key, '[')
+		}
+		if i <= 0 {
+			i = len(key)
+		}
+		parts = append(parts, key[:i])
+		key = key[i:]
+	}
+	return parts
+}
+
+// keyShape returns parts with contents of brackets removed and names
+// normalized.
+func keyShape(parts []string, normalize func(string) string) string {
+	var b strings.Builder
+	for _, part := range parts {
+		if part[0] == '[' {
+			_, _ = b.WriteString("[]")
+			continue
+		}
+		names := strings.Split(part, ".")
+		for i := range names {
+			names[i] = normalize(names[i])
+		}
+		_, _ = b.WriteString(strings.Join(names, "."))
+	}
+	return b.String()
+}
+
+// keyShapes returns patterns for typ indexed by their shape.
+func (d *StrictDecoder) keyShapes(typ reflect.Type, params map[string]*constraint) map[string]string {
+	d.shapesMu.Lock()
+	defer d.shapesMu.Unlock()
+	if shapes := d.shapes[typ]; shapes != nil {
+		return shapes
+	}
+
+	shapes := make(map[string]string, len(params))
+	for pattern, c := range params {
+		if c.remain {
+			continue
+		}
+		shape := keyShape(splitKey(pattern), d.keyMatching)
+		if other, ok := shapes[shape]; ok && params[other] != c {
+			if other > pattern {
+				other, pattern = pattern, other
+			}
+			panic(fmt.Sprintf("params %q and %q are same with KeyMatching", other, pattern))
+		}
+		shapes[shape] = pattern
+	}
+
+	if d.shapes == nil {
+		d.shapes = make(map[reflect.Type]map[string]string)
+	}
+	d.shapes[typ] = shapes
+	return shapes
+}
+
+// matchKeys returns values with keys renamed to match patterns in params
+// according to KeyMatching option.
+func (d *StrictDecoder) matchKeys(errs *Errs, typ reflect.Type, params map[string]*constraint, values url.Values) url.Values {
+	shapes := d.keyShapes(typ, params)
+
+	var matched url.Values
+	renamed := make(map[string]string) // key -> first variant of key
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys) // make choice of value on error stable
+	for _, key := range keys {
+		name, pattern := d.matchKey(shapes, key)
+		if pattern == "" {
+			continue
+		}
+		collision := renamed[name] != ""
+		if collision {
+			errs.Add(params[pattern].alias, "multiple names for same value")
+		} else {
+			renamed[name] = key
+		}
+		if name == key {
+			continue
+		}
+		if matched == nil {
+			matched = make(url.Values, len(values))
+			for key, vals := range values {
+				matched[key] = vals
+			}
+		}
+		delete(matched, key)
+		if !collision {
+			matched[name] = values[key]
+		}
+	}
+	if matched == nil {
+		return values
+	}
+	return matched
+}
+
+// matchKey returns key renamed to match pattern from shapes and this
+// pattern or key and empty string if key doesn't match any pattern.
+func (d *StrictDecoder) matchKey(shapes map[string]string, key string) (name, pattern string) {
+	parts := splitKey(key)
+	pattern, ok := shapes[keyShape(parts, d.keyMatching)]
+	if !ok {
+		return key, ""
+	}
+	return realKey(parts, splitKey(pattern)), pattern
+}
+
+// realKey returns key with names from pattern and brackets from key.
+func realKey(key, pattern []string) string {
+	var b strings.Builder
+	for i := range key {
+		if key[i][0] == '[' {
+			_, _ = b.WriteString(key[i])
+		} else {
+			_, _ = b.WriteString(pattern[i])
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,62 @@
+package urlvalues
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/powerman/check"
+)
+
+func TestSplitKey(tt *testing.T) {
+	t := check.T(tt)
+	t.Nil(splitKey(""))
+	t.DeepEqual(splitKey("a.b"), []string{"a.b"})
+	t.DeepEqual(splitKey("a[0].b[key][1]"), []string{"a", "[0]", ".b", "[key]", "[1]"})
+	t.DeepEqual(splitKey("[x]a["), []string{"[x]", "a", "["})
+}
+
+func TestKeyMatching(tt *testing.T) {
+	t := check.T(tt)
+	type Item struct {
+		ItemName string `form:"item_name"`
+	}
+	var data struct {
+		PageSize int `form:"page_size"`
+		Items    []Item
+		M        map[string]int
+	}
+	d := NewStrictDecoder(KeyMatching(MatchIgnoreCaseAndSeparators))
+	values := url.Values{
+		"PageSize":           {"10"},
+		"items[0].itemName":  {"one"},
+		"ITEMS[1].Item-Name": {"two"},
+		"m[Key]":             {"42"},
+		"unknown":            {"x"},
+	}
+	errs := d.Decode(&data, values)
+	t.DeepEqual(errs.(Errs).Values, url.Values{"-": {"unknown"}})
+	delete(values, "unknown")
+	t.Nil(d.Decode(&data, values))
+	t.Equal(data.PageSize, 10)
+	t.DeepEqual(data.Items, []Item{{ItemName: "one"}, {ItemName: "two"}})
+	t.DeepEqual(data.M, map[string]int{"Key": 42})
+	t.Len(values, 4)
+
+	errs = d.Decode(&data, url.Values{
+		"page_size": {"10"},
+		"pagesize":  {"20"},
+	})
+	t.DeepEqual(errs, Errs{Values: url.Values{
+		"page_size": {"multiple names for same value"},
+	}})
+
+	d = NewStrictDecoder(KeyMatching(MatchCaseInsensitive))
+	errs = d.Decode(&data, url.Values{"PAGE_SIZE": {"10"}, "pagesize": {"20"}})
+	t.DeepEqual(errs.(Errs).Values, url.Values{"-": {"pagesize"}})
+
+	var bad struct {
+		A int
+		B int `form:"a"`
+	}
+	t.PanicMatch(func() { _ = d.Decode(&bad, url.Values{}) }, `params "A" and "a" are same`)
+}
@@ -0,0 +1,87 @@
+package urlvalues
+
+import (
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// fieldNaming is used to make decoderOpts comparable.
+//
+// It's created for each decoder, so it also keeps introspection caches
+// for this decoder instead of global caches (to avoid leaking entries).
+type fieldNaming struct {
+	name        func(fieldName string) string
+	paramsCache map[decoderOpts]map[reflect.Type]map[string]*constraint
+	hooksCache  map[decoderOpts]map[reflect.Type]bool
+}
+
+// NamingStrategy return an option for NewStrictDecoder.
+//
+// With this option names of fields without name in tag will be converted
+// using naming, which can be one of SnakeCase, CamelCase, KebabCase
+// or custom function.
+//
+// Decoders created with this option won't share introspection cache (even
+// with same naming), so it's better to create one decoder per naming
+// strategy and reuse it.
+func NamingStrategy(naming func(fieldName string) string) StrictDecoderOption {
+	return StrictDecoderOption(func(d *StrictDecoder) {
+		d.decoderOpts.naming = &fieldNaming{
+			name:        naming,
+			paramsCache: make(map[decoderOpts]map[reflect.Type]map[string]*constraint),
+			hooksCache:  make(map[decoderOpts]map[reflect.Type]bool),
+		}
+	})
+}
+
+// SnakeCase converts field name like "UserID" to "user_id".
+func SnakeCase(fieldName string) string {
+	return strings.ToLower(strings.Join(splitWords(fieldName), "_"))
+}
+
+// KebabCase converts field name like "UserID" to "user-id".
+func KebabCase(fieldName string) string {
+	return strings.ToLower(strings.Join(splitWords(fieldName), "-"))
+}
+
+// CamelCase converts field name like "UserID" to "userId".
+func CamelCase(fieldName string) string {
+	words := splitWords(fieldName)
+	for i := range words {
+		words[i] = strings.ToLower(words[i])
+		if i > 0 {
+			r := []rune(words[i])
+			r[0] = unicode.ToUpper(r[0])
+			words[i] = string(r)
+		}
+	}
+	return strings.Join(words, "")
+}
+
+// splitWords splits field name like "HTTPServerID2_Name" to words like
+// "HTTP", "Server", "ID2", "Name".
+func splitWords(fieldName string) (words []string) {
+	r := []rune(fieldName)
+	start := 0
+	for i := 0; i <= len(r); i++ {
+		switch {
+		case i == len(r):
+		case r[i] == '_':
+		case i == start:
+			continue
+		case !unicode.IsUpper(r[i]):
+			continue
+		case unicode.IsUpper(r[i-1]) && (i+1 == len(r) || !unicode.IsLower(r[i+1])):
+			continue
+		}
+		if start < i {
+			words = append(words, string(r[start:i]))
+		}
+		start = i
+		if i < len(r) && r[i] == '_' {
+			start++
+		}
+	}
+	return words
+}
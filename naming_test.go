@@ -0,0 +1,102 @@
+package urlvalues
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/go-playground/form"
+	"github.com/powerman/check"
+)
+
+func TestNamingFuncs(tt *testing.T) {
+	t := check.T(tt)
+	tests := []struct {
+		name  string
+		snake string
+		camel string
+		kebab string
+	}{
+		{"", "", "", ""},
+		{"A", "a", "a", "a"},
+		{"ID", "id", "id", "id"},
+		{"PageSize", "page_size", "pageSize", "page-size"},
+		{"UserID", "user_id", "userId", "user-id"},
+		{"HTTPServer", "http_server", "httpServer", "http-server"},
+		{"Field2Name", "field2_name", "field2Name", "field2-name"},
+		{"Page_Size", "page_size", "pageSize", "page-size"},
+		{"Ünicode", "ünicode", "ünicode", "ünicode"},
+	}
+	for _, tc := range tests {
+		t.Equal(SnakeCase(tc.name), tc.snake, tc.name)
+		t.Equal(CamelCase(tc.name), tc.camel, tc.name)
+		t.Equal(KebabCase(tc.name), tc.kebab, tc.name)
+	}
+}
+
+type namingPart struct {
+	PartID int
+}
+
+func TestNamingStrategy(tt *testing.T) {
+	t := check.T(tt)
+	type Item struct {
+		ItemName string
+	}
+	type Data struct {
+		namingPart
+		PageSize int
+		Query    string `form:"q"`
+		UserID   int    `form:",required"`
+		Items    []Item
+		Skip     int `form:"-"`
+	}
+	var data Data
+	d := NewStrictDecoder(NamingStrategy(SnakeCase))
+	t.Nil(d.Decode(&data, url.Values{
+		"part_id":            {"1"},
+		"page_size":          {"10"},
+		"q":                  {"query"},
+		"user_id":            {"2"},
+		"items[0].item_name": {"one"},
+	}))
+	t.DeepEqual(data, Data{
+		namingPart: namingPart{PartID: 1},
+		PageSize:   10,
+		Query:      "query",
+		UserID:     2,
+		Items:      []Item{{ItemName: "one"}},
+	})
+	errs := d.Decode(&data, url.Values{"PageSize": {"10"}})
+	t.DeepEqual(errs.(Errs).Values, url.Values{
+		"-":       {"PageSize"},
+		"user_id": {"required"},
+	})
+
+	var patterns []string
+	for _, p := range d.Params(Data{}) {
+		patterns = append(patterns, p.Pattern)
+	}
+	t.Equal(strings.Join(patterns, " "), "items[idx].item_name page_size part_id q user_id")
+
+	d = NewStrictDecoder(NamingStrategy(CamelCase), Mode(form.ModeExplicit))
+	data = Data{}
+	errs = d.Decode(&data, url.Values{"pageSize": {"10"}, "userId": {"2"}})
+	t.DeepEqual(errs.(Errs).Values, url.Values{"-": {"pageSize"}})
+
+	paramsCacheMu.Lock()
+	params := len(paramsCache)
+	paramsCacheMu.Unlock()
+	hooksCacheMu.RLock()
+	hooks := len(hooksCache)
+	hooksCacheMu.RUnlock()
+	for i := 0; i < 3; i++ {
+		_ = NewStrictDecoder(NamingStrategy(SnakeCase)).Decode(&data, url.Values{"user_id": {"2"}})
+	}
+	paramsCacheMu.Lock()
+	t.Equal(len(paramsCache), params)
+	paramsCacheMu.Unlock()
+	hooksCacheMu.RLock()
+	t.Equal(len(hooksCache), hooks)
+	hooksCacheMu.RUnlock()
+}
@@ -0,0 +1,112 @@
+package urlvalues
+
+import (
+	"expvar"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// Observer can be used to collect metrics about Decode calls.
+//
+// Methods must be safe for concurrent use.
+type Observer interface {
+	// OnDecode is called after each Decode (or DecodeWithReport) with
+	// type of decoded struct, duration of Decode and returned error.
+	OnDecode(typ reflect.Type, duration time.Duration, err error)
+	// OnFieldError is called for each error returned by Decode or
+	// reported by ShadowMode option (before OnDecode). Code for
+	// pattern "-" is "unknown" instead of unknown key.
+	OnFieldError(typ reflect.Type, pattern, code string)
+}
+
+// Observe return an option for NewStrictDecoder.
+//
+// With this option Decode will report metrics to observer.
+// See NewExpvarObserver for Observer implementation using expvar.
+func Observe(observer Observer) StrictDecoderOption {
+	return StrictDecoderOption(func(d *StrictDecoder) {
+		d.observer = observer
+	})
+}
+
+// observeErrs calls OnFieldError for each error in errs.
+func (d *StrictDecoder) observeErrs(typ reflect.Type, errs Errs) {
+	if d.observer == nil {
+		return
+	}
+	for pattern, codes := range errs.Values {
+		for _, code := range codes {
+			if pattern == "-" {
+				code = "unknown"
+			}
+			d.observer.OnFieldError(typ, pattern, code)
+		}
+	}
+}
+
+//nolint:gochecknoglobals
+var latencyBuckets = []time.Duration{
+	10 * time.Microsecond,
+	100 * time.Microsecond,
+	time.Millisecond,
+	10 * time.Millisecond,
+	100 * time.Millisecond,
+}
+
+// ExpvarObserver is an Observer which collects metrics using expvar.
+type ExpvarObserver struct {
+	m  *expvar.Map
+	mu sync.Mutex // protects creation of nested maps
+}
+
+// NewExpvarObserver returns Observer which collects metrics in m.
+//
+// For each decoded struct type m will contain a map (using type name as
+// a key) with these metrics:
+//	decodes  - amount of Decode calls
+//	failures - amount of Decode calls which returned error
+//	latency  - map with amount of Decode calls faster than or equal to
+//	           "10µs", "100µs", "1ms", "10ms", "100ms" and "+Inf"
+//	errors   - map with map for each pattern with amount of each code
+//
+// Example:
+//	d := urlvalues.NewStrictDecoder(urlvalues.Observe(
+//		urlvalues.NewExpvarObserver(expvar.NewMap("urlvalues"))))
+func NewExpvarObserver(m *expvar.Map) *ExpvarObserver {
+	return &ExpvarObserver{m: m}
+}
+
+// OnDecode implements Observer.
+func (o *ExpvarObserver) OnDecode(typ reflect.Type, duration time.Duration, err error) {
+	stats := o.submap(o.m, typ.String())
+	stats.Add("decodes", 1)
+	if err != nil {
+		stats.Add("failures", 1)
+	}
+	latency := o.submap(stats, "latency")
+	for _, bucket := range latencyBuckets {
+		if duration <= bucket {
+			latency.Add(bucket.String(), 1)
+		}
+	}
+	latency.Add("+Inf", 1)
+}
+
+// OnFieldError implements Observer.
+func (o *ExpvarObserver) OnFieldError(typ reflect.Type, pattern, code string) {
+	errors := o.submap(o.submap(o.m, typ.String()), "errors")
+	o.submap(errors, pattern).Add(code, 1)
+}
+
+// submap returns map stored in m using key, creating it if needed.
+func (o *ExpvarObserver) submap(m *expvar.Map, key string) *expvar.Map {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	sub, ok := m.Get(key).(*expvar.Map)
+	if !ok {
+		sub = new(expvar.Map).Init()
+		m.Set(key, sub)
+	}
+	return sub
+}
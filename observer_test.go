@@ -0,0 +1,84 @@
+package urlvalues
+
+import (
+	"expvar"
+	"net/url"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/powerman/check"
+)
+
+type testObserver struct {
+	mu      sync.Mutex
+	decodes []error
+	errs    []string
+}
+
+func (o *testObserver) OnDecode(typ reflect.Type, duration time.Duration, err error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.decodes = append(o.decodes, err)
+}
+
+func (o *testObserver) OnFieldError(typ reflect.Type, pattern, code string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.errs = append(o.errs, typ.Name()+" "+pattern+" "+code)
+}
+
+type observerData struct {
+	I int
+	S string `form:",required"`
+}
+
+func TestObserve(tt *testing.T) {
+	t := check.T(tt)
+	var data observerData
+	o := &testObserver{}
+	d := NewStrictDecoder(Observe(o))
+	t.Nil(d.Decode(&data, url.Values{"S": {"ok"}}))
+	err := d.Decode(&data, url.Values{"I": {"x"}, "x": {"1"}, "y": {"2"}})
+	t.NotNil(err)
+	t.DeepEqual(o.decodes, []error{nil, err})
+	sort.Strings(o.errs)
+	t.DeepEqual(o.errs, []string{
+		"observerData - unknown",
+		"observerData - unknown",
+		"observerData I wrong type",
+		"observerData S required",
+	})
+
+	o = &testObserver{}
+	d = NewStrictDecoder(Observe(o), ShadowMode(func(Errs) {}))
+	t.Nil(d.Decode(&data, url.Values{"I": {"1", "2"}, "S": {"ok"}}))
+	t.DeepEqual(o.decodes, []error{nil})
+	t.DeepEqual(o.errs, []string{"observerData I multiple values"})
+}
+
+func TestExpvarObserver(tt *testing.T) {
+	t := check.T(tt)
+	var data observerData
+	m := new(expvar.Map).Init()
+	d := NewStrictDecoder(Observe(NewExpvarObserver(m)))
+	t.Nil(d.Decode(&data, url.Values{"S": {"ok"}}))
+	t.NotNil(d.Decode(&data, url.Values{"I": {"x"}}))
+	t.NotNil(d.Decode(&data, url.Values{"I": {"1"}}))
+
+	stats, ok := m.Get("urlvalues.observerData").(*expvar.Map)
+	if t.True(ok) {
+		t.Equal(stats.Get("decodes").String(), "3")
+		t.Equal(stats.Get("failures").String(), "2")
+		t.Equal(stats.Get("latency").(*expvar.Map).Get("+Inf").String(), "3")
+		t.Equal(stats.Get("errors").String(), `{"I": {"wrong type": 1}, "S": {"required": 2}}`)
+	}
+
+	o := NewExpvarObserver(m)
+	o.OnDecode(reflect.TypeOf(data), 5*time.Millisecond, nil)
+	latency := stats.Get("latency").(*expvar.Map)
+	t.Equal(latency.Get("100ms").String(), "4")
+	t.Equal(latency.Get("+Inf").String(), "4")
+}
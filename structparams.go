@@ -1,8 +1,12 @@
 package urlvalues
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 
@@ -14,6 +18,8 @@ type decoderOpts struct {
 	maxArraySize uint
 	mode         form.Mode
 	tagName      string
+	fallbackTags string // comma-separated tag names used after tagName
+	naming       *fieldNaming
 }
 
 // newDecoderOpts return decoderOpts with default values.
@@ -28,14 +34,305 @@ func newDecoderOpts() decoderOpts {
 // constraint describe properties of url.Values key corresponding to some value
 // in target data structure.
 type constraint struct {
-	alias    string // shortest of all aliases
-	required bool   // true for fields tagged `form:",required"`
-	list     bool   // true for array or slice
-	maxsize  []int  // maxsize(array) or SetMaxArraySize(10000) for slices
+	alias    string       // shortest of all aliases
+	required bool         // true for fields tagged `form:",required"`
+	list     bool         // true for array or slice
+	maxsize  []int        // maxsize(array) or SetMaxArraySize(10000) for slices
+	typ      reflect.Type // type of single value
+	keys     []*mapKey    // constraints for each [key] in pattern
+	remain   bool         // true for field tagged `form:",remain"`
+	dup      DupPolicy    // DupFirst or DupLast for fields tagged `form:",first"` or `form:",last"`
+	conds    []*condition // requirements depending on presence of sibling fields
+	notEmpty bool         // true for field tagged `form:",notempty"`
+	secret   bool         // true for field tagged `form:",secret"`
+	norm     int          // normalizations for field tagged `form:",trim"` etc.
+	groups   []*group     // optional or required structs containing field, outermost first
+	index    []int        // field index, -1 for elements of array/slice/map
+	alts     altNames     // patterns for field tagged `form:",alias=a"` or `form:",deprecated=a"`
+}
+
+// altName describe pattern using field's alias or deprecated name.
+type altName struct {
+	primary    string // pattern using field's name
+	deprecated bool   // true for name from `form:",deprecated=a"`
+	option     string // tag option
+}
+
+// altNames contain altName for each pattern using alias or deprecated name.
+type altNames map[string]*altName
+
+// mapKey describe properties of [key] in url.Values key.
+type mapKey struct {
+	typ     reflect.Type   // type of map key
+	allowed []string       // allowed keys, from `form:",keys=a|b"`
+	pattern *regexp.Regexp // from `form:",keypattern=^[a-z]+$"`
+	max     int            // max amount of keys, from `form:",maxkeys=20"`
+}
+
+// allowKey returns true if key is allowed by mk.
+func (mk *mapKey) allowKey(key string) bool {
+	if mk.allowed != nil {
+		found := false
+		for _, allowed := range mk.allowed {
+			found = found || key == allowed
+		}
+		if !found {
+			return false
+		}
+	}
+	return mk.pattern == nil || mk.pattern.MatchString(key)
+}
+
+// group describe struct field which is optional or required as a whole.
+type group struct {
+	name     string // pattern of struct field
+	optional bool   // true for struct reached through pointer or array/slice/map
+	required bool   // true for struct field tagged `form:",required"`
+}
+
+// groupsActive returns true if innermost optional group in groups is
+// present, which means required fields inside it should be checked.
+func groupsActive(groups []*group, present map[*group]bool) bool {
+	for i := len(groups) - 1; i >= 0; i-- {
+		if groups[i].optional {
+			return present[groups[i]]
+		}
+	}
+	return true
+}
+
+// groupInstance identifies instance of group inside array/slice/map
+// elements by values of [idx] and [key] in group's pattern.
+type groupInstance struct {
+	g    *group
+	inst string // like "[0][a]", empty if group isn't inside array/slice/map
+}
+
+// groupsActiveAt is like groupsActive but checks presence of group
+// instances related to inst.
+func groupsActiveAt(groups []*group, present map[groupInstance]bool, inst string) bool {
+	for i := len(groups) - 1; i >= 0; i-- {
+		if groups[i].optional {
+			return present[groupInstance{groups[i], instancePrefix(inst, depth(groups[i].name))}]
+		}
+	}
+	return true
+}
+
+// elemInstances returns present instances of innermost optional group in
+// groups inside array/slice/map elements with given depth.
+func elemInstances(groups []*group, present map[groupInstance]bool, n int) (instances []string) {
+	for i := len(groups) - 1; i >= 0; i-- {
+		if groups[i].optional && depth(groups[i].name) == n {
+			for gi := range present {
+				if gi.g == groups[i] {
+					instances = append(instances, gi.inst)
+				}
+			}
+			break
+		}
+	}
+	return instances
+}
+
+// depth returns amount of [idx] and [key] in pattern.
+func depth(pattern string) int {
+	return strings.Count(pattern, "[idx]") + strings.Count(pattern, "[key]")
+}
+
+// structDepth returns depth of struct containing field with pattern.
+func structDepth(pattern string) int {
+	return depth(pattern[:strings.LastIndex(pattern, ".")+1])
+}
+
+// instancePrefix returns part of inst (like "[0][a]") with n first values.
+func instancePrefix(inst string, n int) string {
+	end := 0
+	for ; n > 0; n-- {
+		end += strings.IndexByte(inst[end:], ']') + 1
+	}
+	return inst[:end]
+}
+
+// condition is a requirement depending on presence of sibling fields.
+type condition struct {
+	option  string   // tag option, like "required_with=a|b"
+	code    string   // "required" or "excluded"
+	present bool     // apply if any of names is present (false: if none)
+	names   []string // sibling field names, prefixed by addStruct
+}
+
+func newCondition(opt, code string, present bool) *condition {
+	return &condition{
+		option:  opt,
+		code:    code,
+		present: present,
+		names:   strings.Split(opt[strings.IndexByte(opt, '=')+1:], "|"),
+	}
+}
+
+// tagOpts contain options from field's tag.
+type tagOpts struct {
+	required   bool
+	keys       []string
+	keyPattern *regexp.Regexp
+	maxKeys    int
+	mapOnly    string // one of used options which require map field
+	remain     bool
+	dup        DupPolicy
+	conds      []*condition
+	notEmpty   bool
+	secret     bool
+	norm       int
+	leafOpt    string // one of used options which require non-struct field
+	alts       []tagAlt
+}
+
+// tagAlt is a name from `form:",alias=a"` or `form:",deprecated=a"`.
+type tagAlt struct {
+	name       string
+	deprecated bool
+	option     string
+}
+
+// parseTagOpts returns parsed options from field's tag.
+// It panics on malformed option or on unknown option in primary tag
+// (unknown options in other tags are ignored).
+func parseTagOpts(field reflect.StructField, options []string, primary bool) (topts tagOpts) {
+	for _, opt := range options {
+		var err error
+		switch {
+		case opt == "required":
+			topts.required = true
+		case opt == "remain":
+			topts.remain = true
+		case opt == "trim":
+			topts.norm, topts.leafOpt = topts.norm|normTrim, opt
+		case opt == "collapse":
+			topts.norm, topts.leafOpt = topts.norm|normCollapse, opt
+		case opt == "lower":
+			topts.norm, topts.leafOpt = topts.norm|normLower, opt
+		case opt == "upper":
+			topts.norm, topts.leafOpt = topts.norm|normUpper, opt
+		case opt == "notempty":
+			topts.notEmpty, topts.leafOpt = true, opt
+		case opt == "secret":
+			topts.secret, topts.leafOpt = true, opt
+		case opt == "first":
+			topts.dup, topts.leafOpt = DupFirst, opt
+		case opt == "last":
+			topts.dup, topts.leafOpt = DupLast, opt
+		case strings.HasPrefix(opt, "required_with="):
+			topts.conds = append(topts.conds, newCondition(opt, "required", true))
+			topts.leafOpt = opt
+		case strings.HasPrefix(opt, "required_without="):
+			topts.conds = append(topts.conds, newCondition(opt, "required", false))
+			topts.leafOpt = opt
+		case strings.HasPrefix(opt, "excluded_with="):
+			topts.conds = append(topts.conds, newCondition(opt, "excluded", true))
+			topts.leafOpt = opt
+		case strings.HasPrefix(opt, "alias="), strings.HasPrefix(opt, "deprecated="):
+			deprecated := strings.HasPrefix(opt, "deprecated=")
+			for _, name := range strings.Split(opt[strings.IndexByte(opt, '=')+1:], "|") {
+				if name == "" || name == "-" || strings.ContainsAny(name, ".[]") {
+					err = fmt.Errorf("bad name %q", name)
+				}
+				topts.alts = append(topts.alts, tagAlt{name: name, deprecated: deprecated, option: opt})
+			}
+		case opt == "", opt == "omitempty":
+		case strings.HasPrefix(opt, "keys="):
+			topts.keys = strings.Split(strings.TrimPrefix(opt, "keys="), "|")
+			topts.mapOnly = opt
+		case strings.HasPrefix(opt, "keypattern="):
+			topts.keyPattern, err = regexp.Compile(strings.TrimPrefix(opt, "keypattern="))
+			topts.mapOnly = opt
+		case strings.HasPrefix(opt, "maxkeys="):
+			topts.maxKeys, err = strconv.Atoi(strings.TrimPrefix(opt, "maxkeys="))
+			if err == nil && topts.maxKeys <= 0 {
+				err = errors.New("must be positive")
+			}
+			topts.mapOnly = opt
+		case !primary:
+		default:
+			panic(fmt.Sprintf("unknown tag option %q on field %q", opt, field.Name))
+		}
+		if err != nil {
+			panic(fmt.Sprintf("bad tag option %q on field %q: %s", opt, field.Name, err))
+		}
+	}
+	if topts.norm&normLower != 0 && topts.norm&normUpper != 0 {
+		panic(fmt.Sprintf("conflicting tag options \"lower\" and \"upper\" on field %q", field.Name))
+	}
+	return topts
+}
+
+// tagName returns name of field for form.Decoder.
+//
+// Unlike form.Decoder it supports multiple options in tag.
+func tagName(opts decoderOpts, field reflect.StructField) string {
+	tag, _ := fieldTag(opts, field)
+	if tag == nil {
+		return "-"
+	}
+	name := tag[0]
+	switch {
+	case name == "-":
+		name += "," // field named "-" instead of ignored by form.Decoder
+	case name != "":
+	case len(tag) > 1 || opts.naming != nil && opts.mode != form.ModeExplicit:
+		name = opts.fieldName(field.Name)
+	}
+	return name
+}
+
+// fieldTag returns field's tag split by comma or nil if field is ignored.
+//
+// Options are taken from first of tags set by TagNames option which is
+// not empty, primary is true if it's the first one (or there are no
+// non-empty tags). Name is taken from first of these tags with non-empty
+// name. Name "-" in first tag means field is ignored. In other tags it
+// works like in encoding/json: tag "-" means field is ignored (if first
+// tag is empty) and tag "-," means field named "-".
+func fieldTag(opts decoderOpts, field reflect.StructField) (tag []string, primary bool) {
+	value := field.Tag.Get(opts.tagName)
+	tag, primary = strings.Split(value, ","), true
+	if tag[0] == "-" {
+		return nil, primary
+	}
+	if opts.fallbackTags == "" {
+		return tag, primary
+	}
+	fallbacks := strings.Split(opts.fallbackTags, ",")
+	if value == "" {
+		for _, name := range fallbacks {
+			if value = field.Tag.Get(name); value != "" {
+				tag, primary = strings.Split(value, ","), false
+				break
+			}
+		}
+		if value == "-" {
+			return nil, primary
+		}
+	}
+	for i := 0; tag[0] == "" && i < len(fallbacks); i++ {
+		if value := field.Tag.Get(fallbacks[i]); value != "-" {
+			tag[0] = strings.Split(value, ",")[0]
+		}
+	}
+	return tag, primary
+}
+
+// fieldName returns param name for field without name in tag.
+func (opts decoderOpts) fieldName(name string) string {
+	if opts.naming != nil {
+		return opts.naming.name(name)
+	}
+	return name
 }
 
 //nolint:gochecknoglobals
 var (
+	typStrings    = reflect.TypeOf([]string(nil))
 	paramsCacheMu sync.Mutex
 	paramsCache   = make(map[decoderOpts]map[reflect.Type]map[string]*constraint)
 )
@@ -43,12 +340,16 @@ var (
 // paramsForStruct introspect given structure and return list of all
 // url.Values keys corresponding to this structure and their constraints.
 func paramsForStruct(opts decoderOpts, typ reflect.Type) (params map[string]*constraint) {
+	cache := paramsCache
+	if opts.naming != nil {
+		cache = opts.naming.paramsCache
+	}
 	paramsCacheMu.Lock()
-	if paramsCache[opts] == nil {
-		paramsCache[opts] = make(map[reflect.Type]map[string]*constraint)
+	if cache[opts] == nil {
+		cache[opts] = make(map[reflect.Type]map[string]*constraint)
 	}
-	if paramsCache[opts][typ] != nil {
-		params = paramsCache[opts][typ]
+	if cache[opts][typ] != nil {
+		params = cache[opts][typ]
 	}
 	paramsCacheMu.Unlock()
 	if params != nil {
@@ -56,19 +357,36 @@ func paramsForStruct(opts decoderOpts, typ reflect.Type) (params map[string]*con
 	}
 
 	params = make(map[string]*constraint)
-	addStruct(opts, typ, "", nil, nil, make(map[string]*constraint), params)
+	addStruct(opts, typ, "", nil, nil, nil, nil, make(map[string]*constraint), params)
+	setPrimaryAliases(params)
+	for _, c := range params {
+		for pattern, alt := range c.alts {
+			if params[pattern] != c {
+				panic(fmt.Sprintf("tag option %q conflicts with param %q", alt.option, pattern))
+			}
+		}
+		for _, cond := range c.conds {
+			for _, name := range cond.names {
+				if params[name] == nil || params[name].remain {
+					panic(fmt.Sprintf("unknown non-struct non-map field %q in tag option %q", name, cond.option))
+				}
+			}
+		}
+	}
 
 	paramsCacheMu.Lock()
-	paramsCache[opts][typ] = params
+	cache[opts][typ] = params
 	paramsCacheMu.Unlock()
 	return params
 }
 
 // addStruct add given structure's fields to params.
 //
-// Parameters namePfx, idxPfx and byIndex are used internally for recursion only.
-func addStruct(opts decoderOpts, typ reflect.Type, namePfx string, idxPfx, maxsize []int, byIndex, params map[string]*constraint) { // nolint:gocyclo
+// Parameters namePfx, idxPfx, maxsize, keys, groups and byIndex are used
+// internally for recursion only.
+func addStruct(opts decoderOpts, typ reflect.Type, namePfx string, idxPfx, maxsize []int, keys []*mapKey, groups []*group, byIndex, params map[string]*constraint) { // nolint:gocyclo
 	seen := make(map[string]bool, typ.NumField())
+	remain := ""
 	typ.FieldByNameFunc(func(shortname string) bool {
 		if seen[shortname] { // we'll handle recursion to anon field manually
 			return false
@@ -80,53 +398,163 @@ func addStruct(opts decoderOpts, typ reflect.Type, namePfx string, idxPfx, maxsi
 			return false
 		}
 
-		var required bool
-		tag := strings.Split(field.Tag.Get(opts.tagName), ",")
-		if opts.mode == form.ModeExplicit && len(tag) == 1 && tag[0] == "" {
+		tag, primary := fieldTag(opts, field)
+		if tag == nil {
 			return false
 		}
-		if tag[0] == "-" {
+		if opts.mode == form.ModeExplicit && len(tag) == 1 && tag[0] == "" {
 			return false
 		}
 		if tag[0] != "" {
 			shortname = tag[0]
+		} else {
+			shortname = opts.fieldName(shortname)
 		}
-		for _, opt := range tag[1:] {
-			switch opt {
-			case "required":
-				required = true
-			case "", "omitempty":
-			default:
-				panic(fmt.Sprintf("unknown tag option %q on field %q", opt, field.Name))
+		topts := parseTagOpts(field, tag[1:], primary)
+		for _, cond := range topts.conds {
+			for i := range cond.names {
+				cond.names[i] = namePfx + cond.names[i]
 			}
 		}
 
 		name := namePfx + shortname
 		index := append(idxPfx, field.Index...)
-		addElem(opts, field.Type, required, name, index, maxsize, byIndex, params)
+		if topts.remain {
+			switch {
+			case remain != "":
+				panic(fmt.Sprintf("multiple remain fields %q and %q", remain, field.Name))
+			case strings.Contains(namePfx, "["):
+				panic(fmt.Sprintf("remain field %q inside array/slice/map", field.Name))
+			case field.Type.Kind() != reflect.Map || field.Type.Key().Kind() != reflect.String || field.Type.Elem() != typStrings:
+				panic(fmt.Sprintf("remain field %q must be url.Values", field.Name))
+			}
+			if len(topts.alts) > 0 {
+				panic(fmt.Sprintf("tag option %q not allowed for remain field %q", topts.alts[0].option, field.Name))
+			}
+			remain = field.Name
+			addRemain(name, index, byIndex, params)
+			return false
+		}
+		addElem(opts, field.Type, topts, name, index, maxsize, keys, groups, byIndex, params)
+		for _, alt := range topts.alts {
+			altParams := make(map[string]*constraint)
+			addElem(opts, field.Type, topts, namePfx+alt.name, index, maxsize, keys, groups, byIndex, altParams)
+			addAlt(params, altParams, name, namePfx+alt.name, alt)
+		}
 
 		return false
 	})
 }
 
+// addAlt add to params altParams added using altPfx instead of name
+// (which is field's pattern).
+func addAlt(params, altParams map[string]*constraint, name, altPfx string, alt tagAlt) {
+	for pattern, c := range altParams {
+		a := &altName{
+			primary:    name + strings.TrimPrefix(pattern, altPfx),
+			deprecated: alt.deprecated,
+			option:     alt.option,
+		}
+		if inner := c.alts[pattern]; inner != nil { // alt name of nested field
+			a.primary = name + strings.TrimPrefix(inner.primary, altPfx)
+			if inner.deprecated && !a.deprecated {
+				a.deprecated, a.option = true, inner.option
+			}
+		}
+		if params[pattern] != nil && params[pattern] != c {
+			panic(fmt.Sprintf("tag option %q conflicts with param %q", alt.option, pattern))
+		}
+		if c.alts == nil {
+			c.alts = make(altNames)
+		}
+		c.alts[pattern] = a
+		params[pattern] = c
+	}
+}
+
+// setPrimaryAliases makes sure alias of constraint isn't alias or
+// deprecated name of field.
+func setPrimaryAliases(params map[string]*constraint) {
+	aliases := make(map[*constraint]string)
+	for pattern, c := range params {
+		if c.alts == nil || c.alts[pattern] != nil {
+			continue
+		}
+		alias, ok := aliases[c]
+		if !ok || len(pattern) < len(alias) || len(pattern) == len(alias) && pattern < alias {
+			aliases[c] = pattern
+		}
+	}
+	for c, alias := range aliases {
+		c.alias = alias
+	}
+}
+
+// addRemain add remain field to params.
+func addRemain(name string, index []int, byIndex, params map[string]*constraint) {
+	idx := fmt.Sprint(index)
+	if byIndex[idx] == nil {
+		byIndex[idx] = &constraint{
+			alias:  name,
+			remain: true,
+			index:  append([]int(nil), index...),
+		}
+	} else if len(name) < len(byIndex[idx].alias) {
+		byIndex[idx].alias = name
+	}
+	params[name] = byIndex[idx]
+}
+
 // addElem add single value of any supported type to params.
 //
-// Parameters name, index and byIndex are used internally for recursion only.
-func addElem(opts decoderOpts, typ reflect.Type, required bool, name string, index, maxsize []int, byIndex, params map[string]*constraint) { //nolint:gocyclo,gocognit
+// Parameters name, index, maxsize, keys, groups and byIndex are used
+// internally for recursion only.
+func addElem(opts decoderOpts, typ reflect.Type, topts tagOpts, name string, index, maxsize []int, keys []*mapKey, groups []*group, byIndex, params map[string]*constraint) { //nolint:gocyclo,gocognit,funlen
+	ptr := false
 	for typ.Kind() == reflect.Ptr {
 		typ = typ.Elem()
+		ptr = true
+	}
+	list := typ.Kind() == reflect.Array || typ.Kind() == reflect.Slice
+	if topts.mapOnly != "" && typ.Kind() != reflect.Map && !(list && complexElem(typ)) {
+		panic(fmt.Sprintf("tag option %q require map type for %q", topts.mapOnly, name))
 	}
 	switch typ.Kind() {
 	case reflect.Chan, reflect.Func, reflect.Interface:
 		return
 	case reflect.Struct: // TODO && no custom handler
-		addStruct(opts, typ, name+".", index, maxsize, byIndex, params)
+		if topts.leafOpt != "" {
+			panic(fmt.Sprintf("tag option %q require non-struct type for %q", topts.leafOpt, name))
+		}
+		elem := strings.HasSuffix(name, "]") // of array/slice/map
+		if ptr || elem || topts.required {
+			groups = append(groups[:len(groups):len(groups)], &group{
+				name:     name,
+				optional: ptr || elem,
+				required: topts.required,
+			})
+		}
+		addStruct(opts, typ, name+".", index, maxsize, keys, groups, byIndex, params)
 		return
 	case reflect.Map:
 		name += "[key]"
+		keys = append(keys[:len(keys):len(keys)], &mapKey{
+			typ:     typ.Key(),
+			allowed: topts.keys,
+			pattern: topts.keyPattern,
+			max:     topts.maxKeys,
+		})
 		if complexElem(typ) {
 			index = append(index, -1)
-			addElem(opts, typ.Elem(), false, name, index, maxsize, byIndex, params)
+			elemOpts := tagOpts{
+				dup:      topts.dup,
+				conds:    topts.conds,
+				notEmpty: topts.notEmpty,
+				secret:   topts.secret,
+				norm:     topts.norm,
+				leafOpt:  topts.leafOpt,
+			}
+			addElem(opts, typ.Elem(), elemOpts, name, index, maxsize, keys, groups, byIndex, params)
 			return
 		}
 	case reflect.Array, reflect.Slice:
@@ -138,19 +566,46 @@ func addElem(opts decoderOpts, typ reflect.Type, required bool, name string, ind
 		if complexElem(typ) {
 			name += "[idx]"
 			index = append(index, -1)
-			addElem(opts, typ.Elem(), false, name, index, maxsize, byIndex, params)
+			elemOpts := tagOpts{
+				keys:       topts.keys,
+				keyPattern: topts.keyPattern,
+				maxKeys:    topts.maxKeys,
+				mapOnly:    topts.mapOnly,
+				dup:        topts.dup,
+				conds:      topts.conds,
+				notEmpty:   topts.notEmpty,
+				secret:     topts.secret,
+				norm:       topts.norm,
+				leafOpt:    topts.leafOpt,
+			}
+			addElem(opts, typ.Elem(), elemOpts, name, index, maxsize, keys, groups, byIndex, params)
 			return
 		}
 	}
 
 	idx := fmt.Sprint(index)
 	if byIndex[idx] == nil {
-		list := typ.Kind() == reflect.Array || typ.Kind() == reflect.Slice
+		elem := typ
+		if list || typ.Kind() == reflect.Map {
+			elem = typ.Elem()
+			for elem.Kind() == reflect.Ptr {
+				elem = elem.Elem()
+			}
+		}
 		byIndex[idx] = &constraint{
 			alias:    name,
-			required: required,
+			required: topts.required,
 			list:     list,
 			maxsize:  maxsize,
+			typ:      elem,
+			keys:     keys,
+			dup:      topts.dup,
+			conds:    topts.conds,
+			notEmpty: topts.notEmpty,
+			secret:   topts.secret,
+			norm:     topts.norm,
+			groups:   groups,
+			index:    append([]int(nil), index...),
 		}
 	} else if len(name) < len(byIndex[idx].alias) || len(name) == len(byIndex[idx].alias) && name < byIndex[idx].alias {
 		byIndex[idx].alias = name
@@ -161,6 +616,12 @@ func addElem(opts decoderOpts, typ reflect.Type, required bool, name string, ind
 	}
 }
 
+// isList returns true if pattern in params accept multiple values.
+func isList(params map[string]*constraint, pattern string) bool {
+	return params[pattern].list && !(strings.HasSuffix(pattern, "[idx]") &&
+		params[pattern] == params[strings.TrimSuffix(pattern, "[idx]")])
+}
+
 func complexElem(typ reflect.Type) bool {
 	typ = typ.Elem()
 	for typ.Kind() == reflect.Ptr {
@@ -175,3 +636,81 @@ func complexElem(typ reflect.Type) bool {
 		return false
 	}
 }
+
+// Param describe url.Values key accepted by StrictDecoder.
+type Param struct {
+	Pattern    string       // Key pattern, same as keys in Errs.
+	Alias      string       // Shortest of all patterns for same value (not using alias or deprecated name).
+	Required   bool         // True for fields tagged `form:",required"`.
+	List       bool         // True if multiple values are accepted.
+	MaxSize    []int        // Max size for each array/slice in Pattern.
+	Type       reflect.Type // Type of single value.
+	Keys       []ParamKey   // Constraints for each [key] in Pattern.
+	Remain     bool         // True for field tagged `form:",remain"`.
+	Primary    string       // Pattern using field's name if Pattern use alias or deprecated name.
+	Deprecated bool         // True if Pattern use name from `form:",deprecated=a"`.
+	Secret     bool         // True for field tagged `form:",secret"`.
+}
+
+// ParamKey describe constraints for [key] in Param.Pattern.
+type ParamKey struct {
+	Type    reflect.Type // Type of map key.
+	Allowed []string     // Allowed keys, nil if any key is allowed.
+	Pattern string       // Regexp for allowed keys, empty if any key is allowed.
+	MaxKeys int          // Max amount of keys in map, 0 if not limited.
+}
+
+// Params returns all url.Values keys accepted by Decode for v sorted by
+// Pattern.
+//
+// Param v can be a struct, a pointer to a struct or reflect.Type of a struct.
+func (d *StrictDecoder) Params(v interface{}) []Param {
+	params := paramsForStruct(d.decoderOpts, structType(v))
+	list := make([]Param, 0, len(params))
+	for pattern, c := range params {
+		p := Param{
+			Pattern:  pattern,
+			Alias:    c.alias,
+			Required: c.required,
+			List:     isList(params, pattern),
+			MaxSize:  c.maxsize,
+			Type:     c.typ,
+			Remain:   c.remain,
+			Secret:   c.secret,
+		}
+		if alt := c.alts[pattern]; alt != nil {
+			p.Primary = alt.primary
+			p.Deprecated = alt.deprecated
+		}
+		for _, mk := range c.keys {
+			pk := ParamKey{
+				Type:    mk.typ,
+				Allowed: mk.allowed,
+				MaxKeys: mk.max,
+			}
+			if mk.pattern != nil {
+				pk.Pattern = mk.pattern.String()
+			}
+			p.Keys = append(p.Keys, pk)
+		}
+		list = append(list, p)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Pattern < list[j].Pattern })
+	return list
+}
+
+// structType returns type of struct given as a struct, a pointer to a
+// struct or reflect.Type of a struct.
+func structType(v interface{}) reflect.Type {
+	typ, ok := v.(reflect.Type)
+	if !ok {
+		typ = reflect.TypeOf(v)
+	}
+	if typ != nil && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ == nil || typ.Kind() != reflect.Struct || typ == typTime {
+		panic("v must be a struct")
+	}
+	return typ
+}
@@ -3,12 +3,21 @@ package urlvalues
 import (
 	"net/url"
 	"reflect"
+	"regexp"
 	"testing"
 
 	"github.com/go-playground/form"
 	"github.com/powerman/check"
 )
 
+//nolint:gochecknoglobals
+var (
+	typBool   = reflect.TypeOf(false)
+	typByte   = reflect.TypeOf(byte(0))
+	typInt    = reflect.TypeOf(0)
+	typString = reflect.TypeOf("")
+)
+
 func TestParamsEmpty(tt *testing.T) {
 	t := check.T(tt)
 	var data struct{}
@@ -22,7 +31,7 @@ func TestParamsExported(tt *testing.T) {
 		a string
 	}
 	t.DeepEqual(paramsForStruct(newDecoderOpts(), reflect.TypeOf(data)), map[string]*constraint{
-		"I": {alias: "I"},
+		"I": {alias: "I", typ: typInt, index: []int{0}},
 	})
 	t.Nil(form.NewDecoder().Decode(&data, url.Values{
 		"I": {"42"},
@@ -45,8 +54,8 @@ func TestParamsModeExplicit(tt *testing.T) {
 	opts := newDecoderOpts()
 	opts.mode = form.ModeExplicit
 	t.DeepEqual(paramsForStruct(opts, reflect.TypeOf(data)), map[string]*constraint{
-		"b": {alias: "b"},
-		"Z": {alias: "Z"},
+		"b": {alias: "b", typ: typBool, index: []int{0}},
+		"Z": {alias: "Z", typ: typString, index: []int{5}},
 	})
 	decoder := form.NewDecoder()
 	decoder.SetMode(opts.mode)
@@ -73,7 +82,7 @@ func TestParamsIgnored(tt *testing.T) {
 		A string `form:"-"`
 	}
 	t.DeepEqual(paramsForStruct(newDecoderOpts(), reflect.TypeOf(data)), map[string]*constraint{
-		"I": {alias: "I"},
+		"I": {alias: "I", typ: typInt, index: []int{0}},
 	})
 }
 
@@ -84,8 +93,8 @@ func TestParamsAlias(tt *testing.T) {
 		A string `form:"a"`
 	}
 	t.DeepEqual(paramsForStruct(newDecoderOpts(), reflect.TypeOf(data)), map[string]*constraint{
-		"I": {alias: "I"},
-		"a": {alias: "a"},
+		"I": {alias: "I", typ: typInt, index: []int{0}},
+		"a": {alias: "a", typ: typString, index: []int{1}},
 	})
 }
 
@@ -96,11 +105,35 @@ func TestParamsRequired(tt *testing.T) {
 		A string `form:",required"`
 	}
 	t.DeepEqual(paramsForStruct(newDecoderOpts(), reflect.TypeOf(data)), map[string]*constraint{
-		"I": {alias: "I"},
-		"A": {alias: "A", required: true},
+		"I": {alias: "I", typ: typInt, index: []int{0}},
+		"A": {alias: "A", required: true, typ: typString, index: []int{1}},
 	})
 }
 
+func TestParamsMapKeys(tt *testing.T) {
+	t := check.T(tt)
+	var data struct {
+		A map[string]string          `form:",keys=a|b|c"`
+		P map[string]struct{ I int } `form:"p,keypattern=^[a-z_]+$,maxkeys=20"`
+	}
+	params := paramsForStruct(newDecoderOpts(), reflect.TypeOf(data))
+	t.DeepEqual(params["A[key]"].keys, []*mapKey{{typ: typString, allowed: []string{"a", "b", "c"}}})
+	t.DeepEqual(params["p[key].I"].keys, []*mapKey{{typ: typString, pattern: regexp.MustCompile(`^[a-z_]+$`), max: 20}})
+
+	var bad1 struct {
+		S string `form:",keys=a"`
+	}
+	var bad2 struct {
+		M map[string]int `form:",maxkeys=0"`
+	}
+	var bad3 struct {
+		M map[string]int `form:",keypattern=("`
+	}
+	t.PanicMatch(func() { paramsForStruct(newDecoderOpts(), reflect.TypeOf(bad1)) }, `"keys=a" require map .* "S"`)
+	t.PanicMatch(func() { paramsForStruct(newDecoderOpts(), reflect.TypeOf(bad2)) }, `bad tag option "maxkeys=0" .* "M"`)
+	t.PanicMatch(func() { paramsForStruct(newDecoderOpts(), reflect.TypeOf(bad3)) }, `bad tag option "keypattern=\(" .* "M"`)
+}
+
 func TestParamsList(tt *testing.T) {
 	t := check.T(tt)
 	var data struct {
@@ -110,14 +143,14 @@ func TestParamsList(tt *testing.T) {
 		S  []int
 	}
 	t.DeepEqual(paramsForStruct(newDecoderOpts(), reflect.TypeOf(data)), map[string]*constraint{
-		"A":       {alias: "A", list: true, maxsize: []int{3}},
-		"A[idx]":  {alias: "A", list: true, maxsize: []int{3}},
-		"B1":      {alias: "B1", list: true, maxsize: []int{5}},
-		"B1[idx]": {alias: "B1", list: true, maxsize: []int{5}},
-		"B2":      {alias: "B2", list: true, maxsize: []int{10000}},
-		"B2[idx]": {alias: "B2", list: true, maxsize: []int{10000}},
-		"S":       {alias: "S", list: true, maxsize: []int{10000}},
-		"S[idx]":  {alias: "S", list: true, maxsize: []int{10000}},
+		"A":       {alias: "A", list: true, maxsize: []int{3}, typ: typInt, index: []int{0}},
+		"A[idx]":  {alias: "A", list: true, maxsize: []int{3}, typ: typInt, index: []int{0}},
+		"B1":      {alias: "B1", list: true, maxsize: []int{5}, typ: typByte, index: []int{1}},
+		"B1[idx]": {alias: "B1", list: true, maxsize: []int{5}, typ: typByte, index: []int{1}},
+		"B2":      {alias: "B2", list: true, maxsize: []int{10000}, typ: typByte, index: []int{2}},
+		"B2[idx]": {alias: "B2", list: true, maxsize: []int{10000}, typ: typByte, index: []int{2}},
+		"S":       {alias: "S", list: true, maxsize: []int{10000}, typ: typInt, index: []int{3}},
+		"S[idx]":  {alias: "S", list: true, maxsize: []int{10000}, typ: typInt, index: []int{3}},
 	})
 }
 
@@ -132,15 +165,15 @@ func TestParamsPtr(tt *testing.T) {
 		Z  **string
 	}
 	t.DeepEqual(paramsForStruct(newDecoderOpts(), reflect.TypeOf(data)), map[string]*constraint{
-		"A":       {alias: "A", list: true, maxsize: []int{3}},
-		"A[idx]":  {alias: "A", list: true, maxsize: []int{3}},
-		"I":       {alias: "I"},
-		"M[key]":  {alias: "M[key]"},
-		"S":       {alias: "S", list: true, maxsize: []int{10000}},
-		"S[idx]":  {alias: "S", list: true, maxsize: []int{10000}},
-		"SS":      {alias: "SS", list: true, maxsize: []int{10000}},
-		"SS[idx]": {alias: "SS", list: true, maxsize: []int{10000}},
-		"Z":       {alias: "Z"},
+		"A":       {alias: "A", list: true, maxsize: []int{3}, typ: typInt, index: []int{0}},
+		"A[idx]":  {alias: "A", list: true, maxsize: []int{3}, typ: typInt, index: []int{0}},
+		"I":       {alias: "I", typ: typInt, index: []int{1}},
+		"M[key]":  {alias: "M[key]", typ: typString, keys: []*mapKey{{typ: typString}}, index: []int{2}},
+		"S":       {alias: "S", list: true, maxsize: []int{10000}, typ: typInt, index: []int{3}},
+		"S[idx]":  {alias: "S", list: true, maxsize: []int{10000}, typ: typInt, index: []int{3}},
+		"SS":      {alias: "SS", list: true, maxsize: []int{10000}, typ: typInt, index: []int{4}},
+		"SS[idx]": {alias: "SS", list: true, maxsize: []int{10000}, typ: typInt, index: []int{4}},
+		"Z":       {alias: "Z", typ: typString, index: []int{5}},
 	})
 	t.Nil(form.NewDecoder().Decode(&data, url.Values{
 		"A":      {"10"},
@@ -233,50 +266,54 @@ type (
 func TestParamsComplex(tt *testing.T) {
 	t := check.T(tt)
 	var data DataA
+	gS1 := []*group{{name: "DataB.S1[key]", optional: true}}
+	gS2 := []*group{{name: "DataB.S2[key][idx]", optional: true}}
+	gS3 := []*group{{name: "DataB.S3[idx]", optional: true}}
+	gS4 := []*group{{name: "DataB.S4[idx][idx]", optional: true}}
 	t.DeepEqual(paramsForStruct(newDecoderOpts(), reflect.TypeOf(data)), map[string]*constraint{
-		"A":                         {alias: "A"},
-		"Y.I":                       {alias: "Y.I"},
-		"Y.S":                       {alias: "Y.S"},
-		"Z":                         {alias: "Z"},
-		"DataB.B":                   {alias: "B", required: true},
-		"DataB.M[key]":              {alias: "M[key]"},
-		"DataB.S1[key].C":           {alias: "S1[key].C", list: true, maxsize: []int{10000}},
-		"DataB.S1[key].C[idx]":      {alias: "S1[key].C", list: true, maxsize: []int{10000}},
-		"DataB.S1[key].Z":           {alias: "S1[key].Z"},
-		"DataB.S2[key][idx].C":      {alias: "S2[key][idx].C", list: true, maxsize: []int{10000, 10000}},
-		"DataB.S2[key][idx].C[idx]": {alias: "S2[key][idx].C", list: true, maxsize: []int{10000, 10000}},
-		"DataB.S2[key][idx].Z":      {alias: "S2[key][idx].Z", maxsize: []int{10000}},
-		"DataB.S3[idx].C":           {alias: "S3[idx].C", list: true, maxsize: []int{10000, 10000}},
-		"DataB.S3[idx].C[idx]":      {alias: "S3[idx].C", list: true, maxsize: []int{10000, 10000}},
-		"DataB.S3[idx].Z":           {alias: "S3[idx].Z", maxsize: []int{10000}},
-		"DataB.S4[idx][idx].C":      {alias: "S4[idx][idx].C", list: true, maxsize: []int{2, 2, 10000}},
-		"DataB.S4[idx][idx].C[idx]": {alias: "S4[idx][idx].C", list: true, maxsize: []int{2, 2, 10000}},
-		"DataB.S4[idx][idx].Z":      {alias: "S4[idx][idx].Z", maxsize: []int{2, 2}},
-		"DataB.zz":                  {alias: "DataB.zz"},
-		"DataB.DataC.C":             {alias: "DataB.C", list: true, maxsize: []int{10000}},
-		"DataB.DataC.C[idx]":        {alias: "DataB.C", list: true, maxsize: []int{10000}},
-		"DataB.DataC.Z":             {alias: "DataB.DataC.Z"},
-		"DataB.C":                   {alias: "DataB.C", list: true, maxsize: []int{10000}},
-		"DataB.C[idx]":              {alias: "DataB.C", list: true, maxsize: []int{10000}},
-		"B":                         {alias: "B", required: true},
-		"M[key]":                    {alias: "M[key]"},
-		"S1[key].C":                 {alias: "S1[key].C", list: true, maxsize: []int{10000}},
-		"S1[key].C[idx]":            {alias: "S1[key].C", list: true, maxsize: []int{10000}},
-		"S1[key].Z":                 {alias: "S1[key].Z"},
-		"S2[key][idx].C":            {alias: "S2[key][idx].C", list: true, maxsize: []int{10000, 10000}},
-		"S2[key][idx].C[idx]":       {alias: "S2[key][idx].C", list: true, maxsize: []int{10000, 10000}},
-		"S2[key][idx].Z":            {alias: "S2[key][idx].Z", maxsize: []int{10000}},
-		"S3[idx].C":                 {alias: "S3[idx].C", list: true, maxsize: []int{10000, 10000}},
-		"S3[idx].C[idx]":            {alias: "S3[idx].C", list: true, maxsize: []int{10000, 10000}},
-		"S3[idx].Z":                 {alias: "S3[idx].Z", maxsize: []int{10000}},
-		"S4[idx][idx].C":            {alias: "S4[idx][idx].C", list: true, maxsize: []int{2, 2, 10000}},
-		"S4[idx][idx].C[idx]":       {alias: "S4[idx][idx].C", list: true, maxsize: []int{2, 2, 10000}},
-		"S4[idx][idx].Z":            {alias: "S4[idx][idx].Z", maxsize: []int{2, 2}},
-		"DataC.C":                   {alias: "C", list: true, maxsize: []int{10000}},
-		"DataC.C[idx]":              {alias: "C", list: true, maxsize: []int{10000}},
-		"DataC.Z":                   {alias: "DataC.Z"},
-		"C":                         {alias: "C", list: true, maxsize: []int{10000}},
-		"C[idx]":                    {alias: "C", list: true, maxsize: []int{10000}},
+		"A":                         {alias: "A", typ: typString, index: []int{0}},
+		"Y.I":                       {alias: "Y.I", typ: typInt, index: []int{4, 0}},
+		"Y.S":                       {alias: "Y.S", typ: typString, index: []int{4, 1}},
+		"Z":                         {alias: "Z", typ: typString, index: []int{5}},
+		"DataB.B":                   {alias: "B", required: true, typ: typString, index: []int{1, 0}},
+		"DataB.M[key]":              {alias: "M[key]", typ: typInt, keys: []*mapKey{{typ: typInt}}, index: []int{1, 1}},
+		"DataB.S1[key].C":           {alias: "S1[key].C", list: true, maxsize: []int{10000}, typ: typString, keys: []*mapKey{{typ: typString}}, groups: gS1, index: []int{1, 2, -1, 0}},
+		"DataB.S1[key].C[idx]":      {alias: "S1[key].C", list: true, maxsize: []int{10000}, typ: typString, keys: []*mapKey{{typ: typString}}, groups: gS1, index: []int{1, 2, -1, 0}},
+		"DataB.S1[key].Z":           {alias: "S1[key].Z", typ: typString, keys: []*mapKey{{typ: typString}}, groups: gS1, index: []int{1, 2, -1, 1}},
+		"DataB.S2[key][idx].C":      {alias: "S2[key][idx].C", list: true, maxsize: []int{10000, 10000}, typ: typString, keys: []*mapKey{{typ: typString}}, groups: gS2, index: []int{1, 3, -1, -1, 0}},
+		"DataB.S2[key][idx].C[idx]": {alias: "S2[key][idx].C", list: true, maxsize: []int{10000, 10000}, typ: typString, keys: []*mapKey{{typ: typString}}, groups: gS2, index: []int{1, 3, -1, -1, 0}},
+		"DataB.S2[key][idx].Z":      {alias: "S2[key][idx].Z", maxsize: []int{10000}, typ: typString, keys: []*mapKey{{typ: typString}}, groups: gS2, index: []int{1, 3, -1, -1, 1}},
+		"DataB.S3[idx].C":           {alias: "S3[idx].C", list: true, maxsize: []int{10000, 10000}, typ: typString, groups: gS3, index: []int{1, 4, -1, 0}},
+		"DataB.S3[idx].C[idx]":      {alias: "S3[idx].C", list: true, maxsize: []int{10000, 10000}, typ: typString, groups: gS3, index: []int{1, 4, -1, 0}},
+		"DataB.S3[idx].Z":           {alias: "S3[idx].Z", maxsize: []int{10000}, typ: typString, groups: gS3, index: []int{1, 4, -1, 1}},
+		"DataB.S4[idx][idx].C":      {alias: "S4[idx][idx].C", list: true, maxsize: []int{2, 2, 10000}, typ: typString, groups: gS4, index: []int{1, 5, -1, -1, 0}},
+		"DataB.S4[idx][idx].C[idx]": {alias: "S4[idx][idx].C", list: true, maxsize: []int{2, 2, 10000}, typ: typString, groups: gS4, index: []int{1, 5, -1, -1, 0}},
+		"DataB.S4[idx][idx].Z":      {alias: "S4[idx][idx].Z", maxsize: []int{2, 2}, typ: typString, groups: gS4, index: []int{1, 5, -1, -1, 1}},
+		"DataB.zz":                  {alias: "DataB.zz", typ: typString, index: []int{1, 7}},
+		"DataB.DataC.C":             {alias: "DataB.C", list: true, maxsize: []int{10000}, typ: typString, index: []int{1, 6, 0}},
+		"DataB.DataC.C[idx]":        {alias: "DataB.C", list: true, maxsize: []int{10000}, typ: typString, index: []int{1, 6, 0}},
+		"DataB.DataC.Z":             {alias: "DataB.DataC.Z", typ: typString, index: []int{1, 6, 1}},
+		"DataB.C":                   {alias: "DataB.C", list: true, maxsize: []int{10000}, typ: typString, index: []int{1, 6, 0}},
+		"DataB.C[idx]":              {alias: "DataB.C", list: true, maxsize: []int{10000}, typ: typString, index: []int{1, 6, 0}},
+		"B":                         {alias: "B", required: true, typ: typString, index: []int{1, 0}},
+		"M[key]":                    {alias: "M[key]", typ: typInt, keys: []*mapKey{{typ: typInt}}, index: []int{1, 1}},
+		"S1[key].C":                 {alias: "S1[key].C", list: true, maxsize: []int{10000}, typ: typString, keys: []*mapKey{{typ: typString}}, groups: gS1, index: []int{1, 2, -1, 0}},
+		"S1[key].C[idx]":            {alias: "S1[key].C", list: true, maxsize: []int{10000}, typ: typString, keys: []*mapKey{{typ: typString}}, groups: gS1, index: []int{1, 2, -1, 0}},
+		"S1[key].Z":                 {alias: "S1[key].Z", typ: typString, keys: []*mapKey{{typ: typString}}, groups: gS1, index: []int{1, 2, -1, 1}},
+		"S2[key][idx].C":            {alias: "S2[key][idx].C", list: true, maxsize: []int{10000, 10000}, typ: typString, keys: []*mapKey{{typ: typString}}, groups: gS2, index: []int{1, 3, -1, -1, 0}},
+		"S2[key][idx].C[idx]":       {alias: "S2[key][idx].C", list: true, maxsize: []int{10000, 10000}, typ: typString, keys: []*mapKey{{typ: typString}}, groups: gS2, index: []int{1, 3, -1, -1, 0}},
+		"S2[key][idx].Z":            {alias: "S2[key][idx].Z", maxsize: []int{10000}, typ: typString, keys: []*mapKey{{typ: typString}}, groups: gS2, index: []int{1, 3, -1, -1, 1}},
+		"S3[idx].C":                 {alias: "S3[idx].C", list: true, maxsize: []int{10000, 10000}, typ: typString, groups: gS3, index: []int{1, 4, -1, 0}},
+		"S3[idx].C[idx]":            {alias: "S3[idx].C", list: true, maxsize: []int{10000, 10000}, typ: typString, groups: gS3, index: []int{1, 4, -1, 0}},
+		"S3[idx].Z":                 {alias: "S3[idx].Z", maxsize: []int{10000}, typ: typString, groups: gS3, index: []int{1, 4, -1, 1}},
+		"S4[idx][idx].C":            {alias: "S4[idx][idx].C", list: true, maxsize: []int{2, 2, 10000}, typ: typString, groups: gS4, index: []int{1, 5, -1, -1, 0}},
+		"S4[idx][idx].C[idx]":       {alias: "S4[idx][idx].C", list: true, maxsize: []int{2, 2, 10000}, typ: typString, groups: gS4, index: []int{1, 5, -1, -1, 0}},
+		"S4[idx][idx].Z":            {alias: "S4[idx][idx].Z", maxsize: []int{2, 2}, typ: typString, groups: gS4, index: []int{1, 5, -1, -1, 1}},
+		"DataC.C":                   {alias: "C", list: true, maxsize: []int{10000}, typ: typString, index: []int{2, 0}},
+		"DataC.C[idx]":              {alias: "C", list: true, maxsize: []int{10000}, typ: typString, index: []int{2, 0}},
+		"DataC.Z":                   {alias: "DataC.Z", typ: typString, index: []int{2, 1}},
+		"C":                         {alias: "C", list: true, maxsize: []int{10000}, typ: typString, index: []int{2, 0}},
+		"C[idx]":                    {alias: "C", list: true, maxsize: []int{10000}, typ: typString, index: []int{2, 0}},
 	})
 	t.Nil(form.NewDecoder().Decode(&data, url.Values{
 		"S2[zero][1].C[2]":      {"three"},
@@ -290,3 +327,33 @@ func TestParamsComplex(tt *testing.T) {
 	t.Equal(data.DataB.Z, "two")
 	t.Equal(data.DataC.Z, "one")
 }
+
+func TestParams(tt *testing.T) {
+	t := check.T(tt)
+	type Data struct {
+		I int               `form:"i,required"`
+		S []string          `form:"s"`
+		M map[string][2]int `form:"m,keys=x|y,maxkeys=1"`
+		F struct {
+			N uint
+		} `form:"f"`
+		R url.Values `form:",remain"`
+	}
+	d := NewStrictDecoder()
+	want := []Param{
+		{Pattern: "R", Alias: "R", Remain: true},
+		{Pattern: "f.N", Alias: "f.N", Type: reflect.TypeOf(uint(0))},
+		{Pattern: "i", Alias: "i", Required: true, Type: typInt},
+		{Pattern: "m[key]", Alias: "m[key]", List: true, MaxSize: []int{2}, Type: typInt,
+			Keys: []ParamKey{{Type: typString, Allowed: []string{"x", "y"}, MaxKeys: 1}}},
+		{Pattern: "m[key][idx]", Alias: "m[key]", MaxSize: []int{2}, Type: typInt,
+			Keys: []ParamKey{{Type: typString, Allowed: []string{"x", "y"}, MaxKeys: 1}}},
+		{Pattern: "s", Alias: "s", List: true, MaxSize: []int{10000}, Type: typString},
+		{Pattern: "s[idx]", Alias: "s", MaxSize: []int{10000}, Type: typString},
+	}
+	t.DeepEqual(d.Params(Data{}), want)
+	t.DeepEqual(d.Params(&Data{}), want)
+	t.DeepEqual(d.Params(reflect.TypeOf(Data{})), want)
+	t.PanicMatch(func() { d.Params(42) }, `^v .* struct`)
+	t.PanicMatch(func() { d.Params(nil) }, `^v .* struct`)
+}
@@ -0,0 +1,110 @@
+package urlvalues
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+//nolint:gochecknoglobals
+var reBrackets = regexp.MustCompile(`\[[^\]]*\]`)
+
+// maxSuggestions limits amount of suggestions for unknown key.
+const maxSuggestions = 3
+
+// suggest returns patterns from params which may be meant by unknown key.
+//
+// Suggested patterns are:
+//   - patterns which differ from key only by case
+//   - patterns which require [idx], [key] or .field after key
+//   - patterns within small edit distance from key
+func suggest(params map[string]*constraint, key string) []string {
+	// Compare with contents of all brackets removed.
+	key = reBrackets.ReplaceAllString(key, "[]")
+	lkey := strings.ToLower(key)
+	maxDist := len(key) / 4
+	if maxDist > 2 {
+		maxDist = 2
+	}
+
+	type suggestion struct {
+		pattern string
+		score   int
+	}
+	var found []suggestion
+	for pattern, c := range params {
+		if c.remain || c.alts[pattern] != nil && c.alts[pattern].deprecated {
+			continue
+		}
+		short := reBrackets.ReplaceAllString(pattern, "[]")
+		lpattern := strings.ToLower(short)
+		switch {
+		case short == key:
+		case lpattern == lkey:
+			found = append(found, suggestion{pattern, 0})
+		case strings.HasPrefix(short, key+"[") || strings.HasPrefix(short, key+"."):
+			found = append(found, suggestion{pattern, 1})
+		default:
+			if dist := editDistance(lkey, lpattern, maxDist); dist <= maxDist {
+				found = append(found, suggestion{pattern, 1 + dist})
+			}
+		}
+	}
+	if len(found) == 0 {
+		return nil
+	}
+
+	sort.Slice(found, func(i, j int) bool {
+		if found[i].score != found[j].score {
+			return found[i].score < found[j].score
+		}
+		return found[i].pattern < found[j].pattern
+	})
+	if len(found) > maxSuggestions {
+		found = found[:maxSuggestions]
+	}
+	patterns := make([]string, len(found))
+	for i := range found {
+		patterns[i] = found[i].pattern
+	}
+	return patterns
+}
+
+// editDistance returns edit distance (with transpositions) between a and b
+// or any value greater than max if distance is greater than max.
+func editDistance(a, b string, max int) int {
+	if diff := len(a) - len(b); diff > max || -diff > max {
+		return max + 1
+	}
+	prev2 := make([]int, len(b)+1)
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min3(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] && prev2[j-2]+1 < cur[j] {
+				cur[j] = prev2[j-2] + 1
+			}
+		}
+		prev2, prev, cur = prev, cur, prev2
+	}
+	return prev[len(b)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
@@ -0,0 +1,74 @@
+package urlvalues
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/powerman/check"
+)
+
+func TestSuggest(tt *testing.T) {
+	t := check.T(tt)
+	type Filter struct {
+		Name string `form:"name"`
+	}
+	var data struct {
+		Limit  int               `form:"limit"`
+		Offset int               `form:"offset"`
+		Filter Filter            `form:"filter"`
+		M      map[string]string `form:"m"`
+		S      []Filter          `form:"s"`
+		Rest   url.Values        `form:"rest,remain"`
+	}
+	params := paramsForStruct(newDecoderOpts(), reflect.TypeOf(data))
+	tests := []struct {
+		key  string
+		want []string
+	}{
+		{"limti", []string{"limit"}},
+		{"LIMIT", []string{"limit"}},
+		{"ofset", []string{"offset"}},
+		{"filter.Name", []string{"filter.name"}},
+		{"filter.nmae", []string{"filter.name"}},
+		{"filter", []string{"filter.name"}},
+		{"m", []string{"m[key]"}},
+		{"s[0]", []string{"s[idx].name"}},
+		{"s[0].Nam", []string{"s[idx].name"}},
+		{"x", nil},
+		{"rest", nil},
+		{"something", nil},
+	}
+	for _, tc := range tests {
+		t.DeepEqual(suggest(params, tc.key), tc.want, tc.key)
+	}
+}
+
+func TestEditDistance(tt *testing.T) {
+	t := check.T(tt)
+	t.Equal(editDistance("", "", 2), 0)
+	t.Equal(editDistance("abc", "abc", 2), 0)
+	t.Equal(editDistance("abc", "acb", 2), 1)
+	t.Equal(editDistance("abc", "bca", 2), 2)
+	t.Equal(editDistance("abc", "abcd", 2), 1)
+	t.Equal(editDistance("kitten", "sitting", 3), 3)
+	t.Equal(editDistance("a", "abcd", 2), 3)
+}
+
+func TestUnknownSuggestions(tt *testing.T) {
+	t := check.T(tt)
+	var data struct {
+		Limit int `form:"limit"`
+	}
+	d := NewStrictDecoder()
+	errs := d.Decode(&data, url.Values{"limti": {"10"}})
+	t.DeepEqual(errs.(Errs).Values, url.Values{"-": {"limti"}})
+	t.DeepEqual(errs.(Errs).Details("-"), []*FieldError{{
+		Pattern:     "-",
+		Code:        "limti",
+		Key:         "limti",
+		Index:       -1,
+		Suggestions: []string{"limit"},
+	}})
+	t.Equal(errs.(Errs).Details("-")[0].Error(), `-: limti (did you mean "limit"?)`)
+}
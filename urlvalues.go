@@ -7,25 +7,64 @@
 //
 // Strict validation rules
 //
-//	- (optional) error on unknown param
+//	- (optional) error on unknown param (details include suggestions)
 //	  - including param matching real, but not qualified enough field name:
 //	    - struct without .field (TODO in case custom handler not registered)
 //	    - map without [key]
 //	- error on array overflow
 //	    - array with out-of-bound [index]
+//	    - [index] which doesn't fit in int
 //	    - too many params for array field
+//	- error on non-canonical [index] (with leading zeros), also such [index]
+//	  which refer to same element as another [index] result in error on
+//	  multiple values
 //	- error on scalar overflow
 //	  - multiple values for non-slice/array field
 //	  - multiple values for same `array[index]` or `map[key]` (in case this
 //	    array/map doesn't have values of slice/array type)
+//	  - unless field is tagged `form:"…,first"` or `form:"…,last"` (or
+//	    DuplicatePolicy option is used) to take first or last value
+//	- error on values for multiple names of same field (like embedded
+//	  struct's field promoted to outer struct or field tagged
+//	  `form:"…,alias=a|b"` or `form:"…,deprecated=a|b"`)
+//	- (optional) error on list given both as multiple values and using [index]
+//	- error on value which can't be converted to field type (details include
+//	  expected kind and bit size, rejected value and list index)
+//	- (optional) error on value (or key, reported using "-") which is not
+//	  valid UTF-8 or contain control characters
+//	- error on [key] which can't be converted to map key type (including
+//	  map keys implementing encoding.TextUnmarshaler), reported using pattern
+//	  up to this [key]
+//	- error on [key] not listed in map field tag option `form:"…,keys=a|b|c"`
+//	  or not matching regexp in `form:"…,keypattern=^[a-z_]+$"` (regexp can't
+//	  contain comma), reported using pattern up to this [key]
+//	- error on more keys in same map than allowed by map field tag option
+//	  `form:"…,maxkeys=20"`, reported using pattern up to this [key]
 //	- error on no values for non-pointer/slice/array field tagged
 //	  `form:"…,required"`
+//	  - fields inside struct reached through pointer or array/slice/map are
+//	    required only if there are values for any field of that struct
+//	    (checked for each array/slice/map element)
+//	  - struct field tagged `form:"…,required"` require values for any of
+//	    its fields
+//	- error on empty values inside list of numbers/bools for field tagged
+//	  `form:"…,notempty"` (or with EmptyAsAbsent option), empty values for
+//	  other fields are handled like missing
+//	- error on no values for field tagged `form:"…,required_with=a|b"` while
+//	  any of sibling fields a or b has values, or tagged
+//	  `form:"…,required_without=a|b"` while none of them has values
+//	- error on values for field tagged `form:"…,excluded_with=a|b"` while any
+//	  of sibling fields a or b has values
+//	  - sibling fields a and b must not be structs or maps
+//	  - inside array/slice/map these conditions are checked for each element
 //	- panic on unknown `form:""` tag option
 package urlvalues
 
 import (
+	"encoding"
 	"fmt"
 	"net/url"
+	"path"
 	"reflect"
 	"regexp"
 	"strconv"
@@ -38,11 +77,21 @@ import (
 
 // Errs contain Decode errors.
 //
-// Errs key can be "-" or pattern for corresponding Decode param values key.
+// Errs key can be "-", "." or pattern for corresponding Decode param
+// values key.
 //
 // Key "-" will contain all keys from Decode param values which are not
 // correspond to any of Decode param v field and thus can't be decoded.
+// Details for these keys include suggestions of similar patterns.
+// Keys captured by field tagged `form:"…,remain"` won't be included.
 // This key won't exists if IgnoreUnknown option is used.
+// Keys matching IgnoreUnknownMatching option won't be included.
+// With RejectInvalidText option it'll also contain invalid keys (these
+// keys have details and are included even with IgnoreUnknown option).
+//
+// Key "." will contain errors related to Decode param v as a whole, added
+// by Validator or URLValuesValidator implemented by v (or its embedded
+// structs) without using any pattern.
 //
 // Pattern is same as values key with map key names replaced with [key] and
 // array/slice indices replaced with [idx].
@@ -50,15 +99,87 @@ import (
 //	FieldA.MapField[something].SliceOfSliceField[42][1].FieldB
 // then related key in Errs will be
 //	FieldA.MapField[key].SliceOfSliceField[idx][idx].FieldB
-type Errs struct{ url.Values }
+//
+// Some errors also have details available using Details.
+//
+// Incompatible change: Errs used to be just struct{ url.Values }, now it
+// also has unexported field with details. Use keyed literal like
+// Errs{Values: v} instead of Errs{v} and compare errs.Values instead of
+// whole Errs (reflect.DeepEqual on Errs also compares details).
+type Errs struct {
+	url.Values
+	details map[string][]*FieldError
+}
 
 func newErrs() Errs { return Errs{Values: make(url.Values)} }
 
+// addError adds err.Code to err.Pattern and keeps err as details.
+func (errs *Errs) addError(err *FieldError) {
+	if errs.details == nil {
+		errs.details = make(map[string][]*FieldError)
+	}
+	errs.Add(err.Pattern, err.Code)
+	errs.details[err.Pattern] = append(errs.details[err.Pattern], err)
+}
+
+// addKeyError adds code to pattern and keeps details with values key.
+func (errs *Errs) addKeyError(pattern, code, key string) {
+	errs.addError(&FieldError{
+		Pattern: pattern,
+		Code:    code,
+		Key:     key,
+		Index:   -1,
+	})
+}
+
+// del removes all errors for pattern.
+func (errs *Errs) del(pattern string) {
+	delete(errs.Values, pattern)
+	delete(errs.details, pattern)
+}
+
+// merge adds all errors from other with patterns prefixed by pattern.
+func (errs *Errs) merge(pattern string, other Errs) {
+	for key, codes := range other.Values {
+		full := key
+		switch {
+		case pattern == "" && key == "":
+			full = rootPattern
+		case pattern == "":
+		case key == "":
+			full = pattern
+		case key[0] == '[':
+			full = pattern + key
+		default:
+			full = pattern + "." + key
+		}
+		for _, code := range codes {
+			errs.Add(full, code)
+		}
+		for _, err := range other.details[key] {
+			err := *err
+			err.Pattern = full
+			if errs.details == nil {
+				errs.details = make(map[string][]*FieldError)
+			}
+			errs.details[full] = append(errs.details[full], &err)
+		}
+	}
+}
+
 // Error return all errors at once using errs.Encode.
 //
 // This is suitable for debugging but not for production error message.
 func (errs Errs) Error() string { return errs.Encode() }
 
+// Details returns details for errors with given pattern.
+//
+// Not all errors have details, so returned slice may be shorter than
+// errs.Values[pattern] or empty.
+func (errs Errs) Details(pattern string) []*FieldError {
+	return errs.details[pattern]
+}
+
 // Any returns one of available errors or empty string if there are no errors.
 func (errs Errs) Any() (pattern string) {
 	for pattern = range errs.Values {
@@ -67,6 +188,49 @@ func (errs Errs) Any() (pattern string) {
 	return ""
 }
 
+// FieldError contain details about one of Errs.
+type FieldError struct {
+	Pattern string       // Key in Errs.
+	Code    string       // Message in Errs.
+	Key     string       // Key in Decode param values, map keys in it may be redacted.
+	Value   string       // Rejected (or normalized) value, may be redacted.
+	Index   int          // Index of rejected value in list, -1 if not a list.
+	Kind    reflect.Kind // Expected kind of value.
+	Bits    int          // Expected bit size of value, 0 if not applicable.
+	Reason  string       // Why value was rejected: "syntax", "overflow", "sign", "utf8", "control" or tag option.
+
+	Suggestions []string // Patterns which may be meant instead of unknown or deprecated key.
+}
+
+// Error returns human-readable description of err.
+func (err *FieldError) Error() string {
+	var b strings.Builder
+	_, _ = b.WriteString(err.Pattern + ": " + err.Code)
+	switch {
+	case err.Kind != reflect.Invalid:
+		_, _ = fmt.Fprintf(&b, " (want %s, got %q", kindName(err.Kind, err.Bits), err.Value)
+		if err.Index >= 0 {
+			_, _ = fmt.Fprintf(&b, " at index %d", err.Index)
+		}
+		_, _ = b.WriteString(": " + err.Reason + ")")
+	case err.Value != "":
+		_, _ = fmt.Fprintf(&b, " (got %q", err.Value)
+		if err.Reason != "" {
+			_, _ = b.WriteString(": " + err.Reason)
+		}
+		_, _ = b.WriteString(")")
+	case err.Reason != "":
+		_, _ = b.WriteString(" (" + err.Reason + ")")
+	case len(err.Suggestions) > 0:
+		_, _ = fmt.Fprintf(&b, " (did you mean %q", err.Suggestions[0])
+		for _, s := range err.Suggestions[1:] {
+			_, _ = fmt.Fprintf(&b, " or %q", s)
+		}
+		_, _ = b.WriteString("?)")
+	}
+	return b.String()
+}
+
 // StrictDecoder wraps https://godoc.org/github.com/go-playground/form#Decoder
 // to add strict validation of url.Values and normalize returned errors.
 //
@@ -75,10 +239,59 @@ func (errs Errs) Any() (pattern string) {
 //	- To make field required (meaning url.Values must contain any value for
 //	  this field, including empty string) tag field with:
 //		`form:"…,required"`
+//	- To normalize values before validation and decoding tag field with
+//	  any of these options (or use TrimSpace option):
+//		`form:"…,trim"`     - remove leading and trailing white space
+//		`form:"…,collapse"` - also replace white space sequences with space
+//		`form:"…,lower"`    - convert to lower case
+//		`form:"…,upper"`    - convert to upper case
+//	- To handle empty values like missing (to make them not satisfy
+//	  required and keep pointer fields nil) tag field with:
+//		`form:"…,notempty"`
+//	  or use EmptyAsAbsent option.
+//	- To accept other names for field (including names of nested fields
+//	  prefixed by these names) tag field with any of these options:
+//		`form:"…,alias=a|b"`      - accept names a and b
+//		`form:"…,deprecated=a|b"` - same, but also report warnings
+//	  Values for these names are decoded like values for field's name.
+//	  These names must not conflict with names of other fields.
+//	  See DecodeWithReport.
+//	- To hide values (and map keys) of field in errors details and
+//	  warnings (they'll be replaced with "[redacted]") tag field with:
+//		`form:"…,secret"`
+//	  See also Redact option.
+//	- To get unknown keys instead of errors add field of type url.Values
+//	  (or map[string][]string) tagged with:
+//		`form:"…,remain"`
+//	  It'll get all unknown keys with prefix of struct containing this
+//	  field (prefix will be removed from keys). Only one such field is
+//	  allowed per struct and it must not be inside array/slice/map.
+//	- After successful decoding Validator and URLValuesValidator
+//	  implemented by v and its nested structs are called to add
+//	  cross-field validation errors.
 type StrictDecoder struct {
 	decoder       *form.Decoder
 	decoderOpts   decoderOpts
 	ignoreUnknown bool
+	ignoreKeys    []string // glob patterns
+	redact        func(pattern, value string) string
+	shadow        func(Errs)
+	warnings      func(Errs)
+	observer      Observer
+	dupPolicy     DupPolicy
+	noMixedLists  bool
+	emptyAsAbsent bool
+	trimSpace     bool
+	validText     bool
+	allowedCtrl   string
+
+	mu         sync.RWMutex          // protects decoder from changes while decoding
+	textKeys   map[reflect.Type]bool // registered map key types
+	textKeysOf map[reflect.Type]bool // structs with registered map key types
+
+	keyMatching func(name string) string
+	shapesMu    sync.Mutex
+	shapes      map[reflect.Type]map[string]string // cache for keyShapes
 }
 
 // StrictDecoderOption is for internal use only and exported just to make
@@ -89,6 +302,11 @@ type StrictDecoderOption func(*StrictDecoder)
 //
 // It's recommended to create one instance (for each opts) and reuse it to
 // enable caching.
+//
+// Map key types implementing encoding.TextUnmarshaler are registered in
+// form.Decoder before first Decode to struct with such map, so values
+// of these types (not only map keys) will be decoded using UnmarshalText
+// by this StrictDecoder since then.
 func NewStrictDecoder(opts ...StrictDecoderOption) *StrictDecoder {
 	d := &StrictDecoder{decoder: form.NewDecoder()}
 	defaults := newDecoderOpts()
@@ -98,6 +316,9 @@ func NewStrictDecoder(opts ...StrictDecoderOption) *StrictDecoder {
 	for _, opt := range opts {
 		opt(d)
 	}
+	d.decoder.RegisterTagNameFunc(func(field reflect.StructField) string {
+		return tagName(d.decoderOpts, field)
+	})
 	return d
 }
 
@@ -131,6 +352,28 @@ func TagName(tagName string) StrictDecoderOption {
 	})
 }
 
+// TagNames return an option for NewStrictDecoder.
+//
+// It works like TagName(tagNames[0]) but fields without (or with empty)
+// tag tagNames[0] will use options from first non-empty tag from rest of
+// tagNames, and fields without name in tag tagNames[0] will use name from
+// first tag with non-empty name, e.g. TagNames("form", "json") lets
+// structs shared with encoding/json avoid duplicating names (even for
+// fields like `json:"user_id" form:",required"`). Tag "-" in rest of
+// tagNames means field is ignored (unless tag tagNames[0] is not empty)
+// and tag "-," means field named "-", like in encoding/json.
+// Option "omitempty" is allowed. Unknown options are ignored in all tags
+// except tagNames[0].
+func TagNames(tagNames ...string) StrictDecoderOption {
+	if len(tagNames) == 0 {
+		panic("TagNames require at least one tag name")
+	}
+	return StrictDecoderOption(func(d *StrictDecoder) {
+		TagName(tagNames[0])(d)
+		d.decoderOpts.fallbackTags = strings.Join(tagNames[1:], ",")
+	})
+}
+
 // IgnoreUnknown return an option for NewStrictDecoder.
 //
 // With this option Decode won't return errors related to unknown keys in
@@ -141,6 +384,129 @@ func IgnoreUnknown() StrictDecoderOption {
 	})
 }
 
+// IgnoreUnknownMatching return an option for NewStrictDecoder.
+//
+// With this option Decode won't return errors related to unknown keys in
+// url.Values matching any of given glob patterns (using path.Match
+// syntax), like "utm_*". It panics on malformed pattern.
+func IgnoreUnknownMatching(patterns ...string) StrictDecoderOption {
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			panic(fmt.Sprintf("bad pattern %q: %s", pattern, err))
+		}
+	}
+	return StrictDecoderOption(func(d *StrictDecoder) {
+		d.ignoreKeys = append(d.ignoreKeys, patterns...)
+	})
+}
+
+// Redact return an option for NewStrictDecoder.
+//
+// With this option all values and map keys (also inside FieldError.Key)
+// included in errors details and warnings will be replaced with result
+// of redact. Values of fields tagged `form:"…,secret"` are always
+// replaced with "[redacted]" without calling redact.
+func Redact(redact func(pattern, value string) string) StrictDecoderOption {
+	return StrictDecoderOption(func(d *StrictDecoder) {
+		d.redact = redact
+	})
+}
+
+// RejectMixedLists return an option for NewStrictDecoder.
+//
+// With this option Decode will report "mixed list notation" error (using
+// list field's pattern) if same list is given both as multiple values of
+// same key and using keys with [index]. Without this option such values
+// are merged by form.Decoder.
+func RejectMixedLists() StrictDecoderOption {
+	return StrictDecoderOption(func(d *StrictDecoder) {
+		d.noMixedLists = true
+	})
+}
+
+// EmptyAsAbsent return an option for NewStrictDecoder.
+//
+// With this option all fields are handled like tagged `form:"…,notempty"`:
+// empty values are handled like missing (so they don't satisfy required
+// and pointer fields stay nil), list with all empty values is handled like
+// missing and empty values in other lists are rejected for numeric and
+// bool fields.
+func EmptyAsAbsent() StrictDecoderOption {
+	return StrictDecoderOption(func(d *StrictDecoder) {
+		d.emptyAsAbsent = true
+	})
+}
+
+// RejectInvalidText return an option for NewStrictDecoder.
+//
+// With this option Decode will report "invalid text" error for values
+// which are not valid UTF-8 or contain control characters (C0, C1 or DEL)
+// except listed in allowed (like "\t\r\n"). Keys with same issues will
+// be reported using "-" (even with IgnoreUnknown option).
+func RejectInvalidText(allowed string) StrictDecoderOption {
+	return StrictDecoderOption(func(d *StrictDecoder) {
+		d.validText = true
+		d.allowedCtrl = allowed
+	})
+}
+
+// TrimSpace return an option for NewStrictDecoder.
+//
+// With this option all fields are handled like tagged `form:"…,trim"`.
+func TrimSpace() StrictDecoderOption {
+	return StrictDecoderOption(func(d *StrictDecoder) {
+		d.trimSpace = true
+	})
+}
+
+// DupPolicy define how to handle multiple values for scalar field.
+type DupPolicy int
+
+// Policies for multiple values for scalar field.
+const (
+	DupError DupPolicy = iota // Report "multiple values" error.
+	DupFirst                  // Use first value.
+	DupLast                   // Use last value.
+)
+
+// DuplicatePolicy return an option for NewStrictDecoder.
+//
+// It sets policy for fields not tagged `form:"…,first"` or `form:"…,last"`.
+// Default is DupError.
+func DuplicatePolicy(policy DupPolicy) StrictDecoderOption {
+	return StrictDecoderOption(func(d *StrictDecoder) {
+		d.dupPolicy = policy
+	})
+}
+
+// ShadowMode return an option for NewStrictDecoder.
+//
+// With this option Decode won't return errors found by strict validation.
+// Instead it'll decode values as leniently as possible: unknown keys,
+// out-of-bounds indices and wrong map keys are dropped, only first value
+// is used in case of "multiple values", extra values are dropped in case
+// of "too many values" and values with wrong type are decoded as empty
+// strings. Then it'll call report once with all errors (including errors
+// from form.Decoder, Validator and URLValuesValidator), if any.
+//
+// It's useful to measure impact of strict validation before enforcing it.
+func ShadowMode(report func(Errs)) StrictDecoderOption {
+	return StrictDecoderOption(func(d *StrictDecoder) {
+		d.shadow = report
+	})
+}
+
+// ReportWarnings return an option for NewStrictDecoder.
+//
+// With this option Decode and DecodeWithReport will call report with
+// Report.Warnings if there are any. Warnings are reported even if Decode
+// returns errors.
+func ReportWarnings(report func(Errs)) StrictDecoderOption {
+	return StrictDecoderOption(func(d *StrictDecoder) {
+		d.warnings = report
+	})
+}
+
 //nolint:gochecknoglobals
 var typTime = reflect.TypeOf(time.Time{})
 
@@ -148,7 +514,42 @@ var typTime = reflect.TypeOf(time.Time{})
 //
 // It'll normalize form.Decoder panics and errors and return nil or Errs.
 // It will panic if called with wrong v, but never panics on wrong values.
-func (d *StrictDecoder) Decode(v interface{}, values url.Values) error { //nolint:gocyclo
+func (d *StrictDecoder) Decode(v interface{}, values url.Values) error {
+	_, err := d.DecodeWithReport(v, values)
+	return err
+}
+
+// Report contain non-fatal results of DecodeWithReport.
+type Report struct {
+	// Warnings has same format as Errs and contain:
+	//   - "deprecated" for values of field's name from
+	//     `form:"…,deprecated=a"` tag option
+	//   - "normalized" for values changed by normalization (like trim),
+	//     details include original value (may be redacted)
+	//   - unknown keys ignored because of IgnoreUnknown or
+	//     IgnoreUnknownMatching options, using "-"
+	Warnings Errs
+}
+
+// DecodeWithReport works like Decode but also returns report with
+// warnings about values which doesn't result in errors but may be worth
+// reporting to client or monitoring. Report is returned even if Decode
+// returns errors.
+func (d *StrictDecoder) DecodeWithReport(v interface{}, values url.Values) (Report, error) {
+	if d.observer == nil {
+		return d.decodeWithReport(v, values)
+	}
+	start := time.Now()
+	report, err := d.decodeWithReport(v, values)
+	typ := reflect.TypeOf(v).Elem()
+	if errs, ok := err.(Errs); ok {
+		d.observeErrs(typ, errs)
+	}
+	d.observer.OnDecode(typ, time.Since(start), err)
+	return report, err
+}
+
+func (d *StrictDecoder) decodeWithReport(v interface{}, values url.Values) (Report, error) { //nolint:gocyclo
 	if values == nil {
 		panic("data must not be nil")
 	}
@@ -157,35 +558,31 @@ func (d *StrictDecoder) Decode(v interface{}, values url.Values) error { //nolin
 		panic("v must be a non-nil pointer to a struct")
 	}
 
-	errs := d.validate(val.Elem().Type(), values)
-	if d.ignoreUnknown && errs.Values["-"] != nil {
-		if len(errs.Values) == 1 {
-			// Hide unknown keys from form.Decoder to avoid panic on unmatched brackets.
-			orig := values
-			values = make(url.Values)
-			for key, value := range orig {
-				values[key] = value
-			}
-			for _, key := range errs.Values["-"] {
-				delete(values, key)
-			}
-		}
-		delete(errs.Values, "-")
+	res := d.validate(val.Elem().Type(), values)
+	errs := res.errs
+	d.ignore(&errs, &res.warns)
+	report := Report{Warnings: res.warns}
+	if d.warnings != nil && len(report.Warnings.Values) > 0 {
+		d.warnings(report.Warnings)
 	}
-	if len(errs.Values) > 0 {
-		return errs
+	if len(errs.Values) > 0 && d.shadow == nil {
+		return report, errs
 	}
 
-	err := d.decode(v, values)
+	d.registerTextKeys(val.Elem().Type())
+	err := d.decode(v, res.values)
 	switch err := err.(type) {
 	case nil:
-		return nil
+		setRemain(val.Elem(), values, res.remain)
+		if hasHooks(d.decoderOpts, val.Type()) {
+			d.callHooks(&errs, val, "")
+		}
 	case form.DecodeErrors:
 		for field, err := range err {
 			msg := err.Error()
 			switch {
 			case strings.Contains(msg, "Map Key"):
-				panic(err) // wrong v
+				panic(err) // wrong v (unsupported map key type)
 			case strings.Contains(msg, "SetMaxArraySize"):
 				panic(err) // wrong v or MaxArraySize
 			case strings.Contains(msg, "Array index"):
@@ -194,12 +591,79 @@ func (d *StrictDecoder) Decode(v interface{}, values url.Values) error { //nolin
 				errs.Add(field, "wrong type")
 			}
 		}
-		return errs
 	case *form.InvalidDecoderError:
 		panic(err) // never here (wrong v, should be handled by panics above)
 	default:
 		panic(err) // never here (unmatched brackets in param name, should be handled by validate)
 	}
+
+	switch {
+	case len(errs.Values) == 0:
+		return report, nil
+	case d.shadow != nil:
+		d.shadowErrs(val.Elem().Type(), errs)
+		return report, nil
+	default:
+		return report, errs
+	}
+}
+
+// Validate checks values using same strict validation rules as Decode to
+// v without decoding them, and returns nil or Errs.
+//
+// Param v can be a struct, a pointer to a struct or reflect.Type of a struct.
+//
+// Unlike Decode it returns errors even with ShadowMode option and doesn't
+// report errors detected only while decoding (by form.Decoder, Validator
+// and URLValuesValidator).
+func (d *StrictDecoder) Validate(v interface{}, values url.Values) error {
+	if values == nil {
+		panic("data must not be nil")
+	}
+	res := d.validate(structType(v), values)
+	errs := res.errs
+	d.ignore(&errs, &res.warns)
+	if d.warnings != nil && len(res.warns.Values) > 0 {
+		d.warnings(res.warns)
+	}
+	if len(errs.Values) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// shadowErrs reports errs found in ShadowMode.
+func (d *StrictDecoder) shadowErrs(typ reflect.Type, errs Errs) {
+	d.observeErrs(typ, errs)
+	d.shadow(errs)
+}
+
+// ignore moves from errs to warns unknown keys which should be ignored
+// according to IgnoreUnknown and IgnoreUnknownMatching options.
+func (d *StrictDecoder) ignore(errs, warns *Errs) {
+	if !d.ignoreUnknown && len(d.ignoreKeys) == 0 {
+		return
+	}
+	details := errs.details["-"]
+	errs.del("-")
+	for _, err := range details {
+		invalid := err.Reason != "" // invalid keys are never ignored
+		if invalid || !d.ignoreUnknown && !d.ignoreKey(err.Key) {
+			errs.addError(err)
+		} else {
+			warns.addError(err)
+		}
+	}
+}
+
+// ignoreKey returns true if unknown key match IgnoreUnknownMatching option.
+func (d *StrictDecoder) ignoreKey(key string) bool {
+	for _, pattern := range d.ignoreKeys {
+		if ok, _ := path.Match(pattern, key); ok {
+			return true
+		}
+	}
+	return false
 }
 
 func (d *StrictDecoder) decode(v interface{}, values url.Values) (err error) {
@@ -208,74 +672,269 @@ func (d *StrictDecoder) decode(v interface{}, values url.Values) (err error) {
 			err = fmt.Errorf("%v", msg) // unmatched brackets in param name
 		}
 	}()
+	d.mu.RLock()
+	defer d.mu.RUnlock()
 	return d.decoder.Decode(v, values)
 }
 
-func (d *StrictDecoder) validate(typ reflect.Type, values url.Values) Errs { //nolint:gocyclo,gocognit,funlen
-	errs := newErrs()
+// validation is a result of validate.
+type validation struct {
+	errs   Errs
+	warns  Errs
+	values url.Values           // without unknown keys, fixed to be decodable
+	remain map[string]remainKey // unknown keys captured by remain fields
+}
+
+// validate returns errors for values which can't be decoded to typ,
+// values fixed to be decoded leniently and unknown keys which will be
+// captured by remain fields.
+func (d *StrictDecoder) validate(typ reflect.Type, values url.Values) validation { //nolint:gocyclo,gocognit,funlen
+	errs, warns := newErrs(), newErrs()
 	params := paramsForStruct(d.decoderOpts, typ)
+	if d.keyMatching != nil {
+		values = d.matchKeys(&errs, typ, params, values)
+	}
+	fixed := fixValues{Values: values}
+	var remainPatterns []string
 
 	// Copy values to be able to delete already processed.
 	valuesCount := make(map[string]int, len(values))
 	for key, val := range values {
+		if d.validText {
+			if reason := checkText(key, d.allowedCtrl); reason != "" {
+				errs.addError(&FieldError{
+					Pattern: "-",
+					Code:    key,
+					Key:     key,
+					Index:   -1,
+					Reason:  reason,
+				})
+				fixed.del(key)
+				continue
+			}
+		}
 		valuesCount[key] = len(val)
 	}
 
 	type lvalueState struct {
-		firstAlias string // used to disallow multiple aliases in values
-		required   bool   // used to detect missing values
+		firstAlias string          // used to disallow multiple aliases in values
+		required   bool            // used to detect missing values
+		conds      []*condition    // used to detect missing or excluded values
+		groups     []*group        // used to detect missing values
+		instances  map[string]bool // instances of containing struct with values
 	}
 	lvalue := make(map[string]*lvalueState, len(params))
 
+	// Presence of values in each array/slice/map element is tracked only
+	// if it's needed to detect missing or excluded values.
+	perInstance := false
+	for _, c := range params {
+		perInstance = perInstance || c.required || len(c.conds) > 0
+		for _, g := range c.groups {
+			perInstance = perInstance || g.required
+		}
+	}
+	presentAt := make(map[groupInstance]bool)
+	addInstance := func(c *constraint, inst string) {
+		if !perInstance {
+			return
+		}
+		state := lvalue[c.alias]
+		if state.instances == nil {
+			state.instances = make(map[string]bool)
+		}
+		state.instances[instancePrefix(inst, structDepth(c.alias))] = true
+		for _, g := range c.groups {
+			presentAt[groupInstance{g, instancePrefix(inst, depth(g.name))}] = true
+		}
+	}
+
+	mapKeys := make(map[string]map[string]bool) // map instance -> seen keys
+	canonNames := make(map[string]bool)         // canonical names for non-canonical keys
+
+	const (
+		listWhole = 1 << iota
+		listIndexed
+	)
+	listForms := make(map[string]int) // list instance -> used forms
+	addListForm := func(pattern, instance string, form int) {
+		if !d.noMixedLists {
+			return
+		}
+		if listForms[instance] != listWhole|listIndexed {
+			listForms[instance] |= form
+			if listForms[instance] == listWhole|listIndexed {
+				errs.Add(pattern, "mixed list notation")
+			}
+		}
+	}
+
 	for pattern, c := range params {
+		if c.remain {
+			remainPatterns = append(remainPatterns, pattern)
+			continue
+		}
 		if lvalue[c.alias] == nil {
 			lvalue[c.alias] = &lvalueState{
 				required: c.required,
+				conds:    c.conds,
+				groups:   c.groups,
 			}
 		}
 
 		found := false
 		if strings.ContainsRune(pattern, '[') {
-			list := c.list && !(strings.HasSuffix(pattern, "[idx]") &&
-				params[pattern] == params[strings.TrimSuffix(pattern, "[idx]")])
+			list := isList(params, pattern)
 			re := compilePattern(pattern)
+			groups := re.SubexpNames()
 			for name, count := range valuesCount {
-				idx := re.FindStringSubmatch(name)
-				if len(idx) == 0 {
+				loc := re.FindStringSubmatchIndex(name)
+				if len(loc) == 0 {
 					continue
 				}
 
-				found = true
 				delete(valuesCount, name)
+				errKey := d.redactKey(pattern, c, name, groups, loc)
 
-				for i := 0; i < len(idx)-1; i++ {
-					index, err := strconv.Atoi(idx[i+1])
-					if err != nil {
-						panic(err)
+				drop := false
+				lastIndex, lastIndexAt := -1, 0
+				nonCanonical, canonAt := false, 0
+				var canon, inst strings.Builder
+				for i, n, k := 1, 0, 0; i < len(groups); i++ {
+					match := name[loc[2*i]:loc[2*i+1]]
+					if groups[i] == "idx" || groups[i] == "key" {
+						inst.WriteString("[" + match + "]")
+					}
+					switch groups[i] {
+					case "idx":
+						index, err := strconv.Atoi(match)
+						switch {
+						case err != nil || index >= c.maxsize[n]:
+							errs.addKeyError(pattern, "index out-of-bounds", errKey)
+							drop = true
+						case match != strconv.Itoa(index):
+							errs.addKeyError(pattern, "non-canonical index", errKey)
+							nonCanonical = true
+						}
+						canon.WriteString(name[canonAt:loc[2*i]])
+						canon.WriteString(strconv.Itoa(index))
+						canonAt = loc[2*i+1]
+						lastIndex, lastIndexAt = index, loc[2*i]-1
+						n++
+					case "key":
+						mk, kpattern := c.keys[k], keyPattern(pattern, k)
+						if !d.checkKey(&errs, kpattern, c, mk, errKey, match) {
+							drop = true
+						}
+						if mk.max > 0 {
+							instance := name[:loc[2*i]-1]
+							if mapKeys[instance] == nil {
+								mapKeys[instance] = make(map[string]bool)
+							}
+							if !mapKeys[instance][match] {
+								mapKeys[instance][match] = true
+								if len(mapKeys[instance]) == mk.max+1 {
+									errs.addKeyError(kpattern, "too many keys", errKey)
+								}
+							}
+							if len(mapKeys[instance]) > mk.max {
+								drop = true
+							}
+						}
+						k++
 					}
-					if index >= c.maxsize[i] {
-						errs.Add(pattern, "index out-of-bounds")
+				}
+				if nonCanonical && !drop {
+					canon.WriteString(name[canonAt:])
+					_, dup := values[canon.String()]
+					if dup || canonNames[canon.String()] {
+						errs.addKeyError(pattern, "multiple values", errKey)
 					}
+					canonNames[canon.String()] = true
 				}
+
+				vals := d.normalize(&warns, pattern, c, errKey, values[name], list)
+				if d.emptyAsAbsent || c.notEmpty {
+					vals = nonEmpty(vals, list)
+					if len(vals) == 0 {
+						fixed.del(name)
+						continue
+					}
+					count = len(vals)
+				}
+				found = true
+				addInstance(c, inst.String())
+
 				if count > 1 {
 					if !list {
-						errs.Add(pattern, "multiple values")
+						vals = d.pickValue(&errs, pattern, c, errKey, vals)
 					} else if count > c.maxsize[len(c.maxsize)-1] {
-						errs.Add(pattern, "too many values")
+						errs.addKeyError(pattern, "too many values", errKey)
 					}
 				}
+
+				index := -1
+				if c.list && !list {
+					index = lastIndex
+					addListForm(strings.TrimSuffix(pattern, "[idx]"), name[:lastIndexAt], listIndexed)
+				} else if list {
+					addListForm(pattern, name, listWhole)
+				}
+				if fixedVals := d.checkValues(&errs, pattern, c, errKey, vals, index); fixedVals != nil {
+					vals = fixedVals
+				}
+				switch {
+				case drop:
+					fixed.del(name)
+				case list:
+					fixed.fix(name, vals, c.maxsize[len(c.maxsize)-1])
+				default:
+					fixed.fix(name, vals, 1)
+				}
+				if alt := c.alts[pattern]; alt != nil {
+					useAlt(&fixed, &warns, pattern, alt, name, errKey)
+				}
 			}
 		} else if count, ok := valuesCount[pattern]; ok {
-			found = true
 			delete(valuesCount, pattern)
 
-			if count > 1 {
+			vals := d.normalize(&warns, pattern, c, pattern, values[pattern], c.list)
+			found = true
+			if d.emptyAsAbsent || c.notEmpty {
+				vals = nonEmpty(vals, c.list)
+				count = len(vals)
+				found = count > 0
+			}
+			if !found {
+				fixed.del(pattern)
+			} else {
+				addInstance(c, "")
+			}
+
+			if found && c.list {
+				addListForm(pattern, pattern, listWhole)
+			}
+			if found && count > 1 {
 				if !c.list {
-					errs.Add(pattern, "multiple values")
+					vals = d.pickValue(&errs, pattern, c, pattern, vals)
 				} else if count > c.maxsize[len(c.maxsize)-1] {
-					errs.Add(pattern, "too many values")
+					errs.addKeyError(pattern, "too many values", pattern)
 				}
 			}
+
+			if fixedVals := d.checkValues(&errs, pattern, c, pattern, vals, -1); fixedVals != nil {
+				vals = fixedVals
+			}
+			switch {
+			case !found:
+			case c.list:
+				fixed.fix(pattern, vals, c.maxsize[len(c.maxsize)-1])
+			default:
+				fixed.fix(pattern, vals, 1)
+			}
+			if alt := c.alts[pattern]; alt != nil && found {
+				useAlt(&fixed, &warns, pattern, alt, pattern, pattern)
+			}
 		}
 
 		if found {
@@ -290,17 +949,445 @@ func (d *StrictDecoder) validate(typ reflect.Type, values url.Values) Errs { //n
 		}
 	}
 
+	present := make(map[*group]bool)
+	for _, state := range lvalue {
+		if state.firstAlias != "" {
+			for _, g := range state.groups {
+				present[g] = true
+			}
+		}
+	}
+	reported := make(map[*group]bool)
 	for pattern, state := range lvalue {
-		if state.required && state.firstAlias == "" {
-			errs.Add(pattern, "required")
+		instances := []string{""}
+		if n := structDepth(pattern); n > 0 && (state.required || len(state.conds) > 0) {
+			instances = elemInstances(state.groups, presentAt, n)
+		}
+		for _, inst := range instances {
+			if state.required && !state.instances[inst] && groupsActiveAt(state.groups, presentAt, inst) {
+				errs.Add(pattern, "required")
+				break
+			}
+		}
+		for i, g := range state.groups {
+			if !g.required || reported[g] {
+				continue
+			}
+			n := depth(g.name)
+			if n == 0 || strings.HasSuffix(g.name, "]") { // not inside element or element itself
+				if !present[g] && groupsActive(state.groups[:i], present) {
+					reported[g] = true
+					errs.Add(g.name, "required")
+				}
+				continue
+			}
+			for _, inst := range elemInstances(state.groups[:i], presentAt, n) {
+				if !presentAt[groupInstance{g, inst}] && groupsActiveAt(state.groups[:i], presentAt, inst) {
+					reported[g] = true
+					errs.Add(g.name, "required")
+					break
+				}
+			}
+		}
+		for _, cond := range state.conds {
+			for _, inst := range instances {
+				if (cond.code == "required") == state.instances[inst] || !groupsActiveAt(state.groups, presentAt, inst) {
+					continue
+				}
+				present := false
+				for _, name := range cond.names {
+					present = present || lvalue[params[name].alias].instances[inst]
+				}
+				if present == cond.present {
+					errs.addError(&FieldError{
+						Pattern: pattern,
+						Code:    cond.code,
+						Index:   -1,
+						Reason:  cond.option,
+					})
+					break
+				}
+			}
 		}
 	}
 
+	var remain map[string]remainKey
 	for name := range valuesCount {
-		errs.Add("-", name)
+		var capture remainKey
+		best := -1
+		for _, pattern := range remainPatterns {
+			prefix := pattern[:strings.LastIndex(pattern, ".")+1]
+			if strings.HasPrefix(name, prefix) && len(prefix) > best {
+				best = len(prefix)
+				capture = remainKey{c: params[pattern], key: name[len(prefix):]}
+			}
+		}
+		fixed.del(name) // also avoids panic in form.Decoder on unmatched brackets
+		if capture.c == nil {
+			err := &FieldError{
+				Pattern: "-",
+				Code:    name,
+				Key:     name,
+				Index:   -1,
+			}
+			if !d.ignoreUnknown {
+				err.Suggestions = suggest(params, name)
+			}
+			errs.addError(err)
+			continue
+		}
+		if remain == nil {
+			remain = make(map[string]remainKey)
+		}
+		remain[name] = capture
 	}
 
-	return errs
+	return validation{errs: errs, warns: warns, values: fixed.Values, remain: remain}
+}
+
+// useAlt renames key matching pattern using alias or deprecated name
+// to use field's name and adds warning (with errKey) for deprecated name.
+func useAlt(fixed *fixValues, warns *Errs, pattern string, alt *altName, key, errKey string) {
+	fixed.rename(key, realKey(splitKey(key), splitKey(alt.primary)))
+	if alt.deprecated {
+		warns.addError(&FieldError{
+			Pattern:     pattern,
+			Code:        "deprecated",
+			Key:         errKey,
+			Index:       -1,
+			Reason:      alt.option,
+			Suggestions: []string{alt.primary},
+		})
+	}
+}
+
+// fixValues is a copy-on-write url.Values modified to be decodable by
+// form.Decoder.
+type fixValues struct {
+	url.Values
+	copied bool
+}
+
+// del removes key.
+func (f *fixValues) del(key string) {
+	if _, ok := f.Values[key]; ok {
+		f.copy()
+		delete(f.Values, key)
+	}
+}
+
+// fix sets key to first max values of vals.
+func (f *fixValues) fix(key string, vals []string, max int) {
+	if len(vals) > max {
+		vals = vals[:max]
+	}
+	orig := f.Values[key]
+	changed := len(vals) != len(orig)
+	for i := 0; i < len(vals) && !changed; i++ {
+		changed = vals[i] != orig[i]
+	}
+	if changed {
+		f.copy()
+		f.Values[key] = vals
+	}
+}
+
+// rename moves values from key to newKey unless newKey already exists.
+func (f *fixValues) rename(key, newKey string) {
+	vals, ok := f.Values[key]
+	if !ok {
+		return
+	}
+	f.del(key)
+	if _, ok := f.Values[newKey]; !ok {
+		f.Values[newKey] = vals
+	}
+}
+
+// copy makes f.Values safe to modify.
+func (f *fixValues) copy() {
+	if f.copied {
+		return
+	}
+	values := make(url.Values, len(f.Values))
+	for key, vals := range f.Values {
+		values[key] = vals
+	}
+	f.Values = values
+	f.copied = true
+}
+
+// remainKey describe unknown key captured by remain field.
+type remainKey struct {
+	c   *constraint // remain field
+	key string      // key relative to struct containing remain field
+}
+
+// setRemain sets remain fields in v to values captured by them.
+func setRemain(v reflect.Value, values url.Values, remain map[string]remainKey) {
+	captured := make(map[*constraint]url.Values)
+	for name, capture := range remain {
+		if captured[capture.c] == nil {
+			captured[capture.c] = make(url.Values)
+		}
+		captured[capture.c][capture.key] = values[name]
+	}
+	for c, vals := range captured {
+		field := v
+		for _, i := range c.index {
+			for field.Kind() == reflect.Ptr {
+				if field.IsNil() {
+					field.Set(reflect.New(field.Type().Elem()))
+				}
+				field = field.Elem()
+			}
+			field = field.Field(i)
+		}
+		field.Set(reflect.ValueOf(vals).Convert(field.Type()))
+	}
+}
+
+// normalize returns vals of key name normalized according to c and
+// options and adds warnings for changed values.
+// Returned slice is a copy if any value was changed.
+func (d *StrictDecoder) normalize(warns *Errs, pattern string, c *constraint, name string, vals []string, list bool) []string {
+	norm := c.norm
+	if d.trimSpace {
+		norm |= normTrim
+	}
+	if norm == 0 {
+		return vals
+	}
+	var res []string
+	for i, val := range vals {
+		val = normalize(norm, val)
+		if val == vals[i] {
+			continue
+		}
+		if res == nil {
+			res = append([]string(nil), vals...)
+		}
+		res[i] = val
+		err := &FieldError{
+			Pattern: pattern,
+			Code:    "normalized",
+			Key:     name,
+			Value:   d.redactValue(pattern, c, vals[i]),
+			Index:   -1,
+		}
+		if list {
+			err.Index = i
+		}
+		warns.addError(err)
+	}
+	if res == nil {
+		return vals
+	}
+	return res
+}
+
+// nonEmpty returns vals without empty values or, if list is true, either
+// vals or nothing if all vals are empty.
+func nonEmpty(vals []string, list bool) []string {
+	var res []string
+	for _, val := range vals {
+		if val != "" {
+			if list {
+				return vals
+			}
+			res = append(res, val)
+		}
+	}
+	return res
+}
+
+// pickValue returns single value from vals according to duplicate policy
+// for c or adds "multiple values" error for key name and returns vals.
+func (d *StrictDecoder) pickValue(errs *Errs, pattern string, c *constraint, name string, vals []string) []string {
+	policy := c.dup
+	if policy == DupError {
+		policy = d.dupPolicy
+	}
+	switch policy {
+	case DupFirst:
+		return vals[:1]
+	case DupLast:
+		return vals[len(vals)-1:]
+	default:
+		errs.addKeyError(pattern, "multiple values", name)
+		return vals
+	}
+}
+
+// checkValues adds errors for vals of key name which can't be converted to
+// type of single value described by c.
+//
+// If index is -1 and c is a list then position in vals is used as index.
+//
+// Returns nil if all vals are ok, otherwise copy of vals with wrong values
+// replaced by empty strings.
+func (d *StrictDecoder) checkValues(errs *Errs, pattern string, c *constraint, name string, vals []string, index int) (fixed []string) {
+	notEmpty := d.emptyAsAbsent || c.notEmpty
+	for i, value := range vals {
+		textReason := ""
+		if d.validText {
+			textReason = checkText(value, d.allowedCtrl)
+		}
+		reason := textReason
+		if reason == "" {
+			reason = checkValue(c.typ, value)
+		}
+		if value == "" && notEmpty && reason == "" {
+			reason = checkNotEmpty(c.typ)
+		}
+		if reason == "" {
+			continue
+		}
+		if fixed == nil {
+			fixed = append([]string(nil), vals...)
+		}
+		fixed[i] = ""
+		value = d.redactValue(pattern, c, value)
+		err := &FieldError{
+			Pattern: pattern,
+			Code:    "wrong type",
+			Key:     name,
+			Value:   value,
+			Index:   index,
+			Kind:    c.typ.Kind(),
+			Bits:    valueBits(c.typ),
+			Reason:  reason,
+		}
+		if c.list && index == -1 {
+			err.Index = i
+		}
+		if textReason != "" {
+			err.Code, err.Kind, err.Bits = "invalid text", reflect.Invalid, 0
+		}
+		errs.addError(err)
+	}
+	return fixed
+}
+
+// redacted is used instead of values of fields tagged `form:",secret"`.
+const redacted = "[redacted]"
+
+// redactValue returns value (or map key) for pattern described by c
+// to be included in errors details and warnings.
+func (d *StrictDecoder) redactValue(pattern string, c *constraint, value string) string {
+	switch {
+	case c.secret:
+		return redacted
+	case d.redact != nil:
+		return d.redact(pattern, value)
+	default:
+		return value
+	}
+}
+
+// redactKey returns key name matching pattern described by c with map
+// keys (located by loc of groups) replaced by redactValue.
+func (d *StrictDecoder) redactKey(pattern string, c *constraint, name string, groups []string, loc []int) string {
+	if !c.secret && d.redact == nil {
+		return name
+	}
+	var b strings.Builder
+	at := 0
+	for i, k := 1, 0; i < len(groups); i++ {
+		if groups[i] != "key" {
+			continue
+		}
+		b.WriteString(name[at:loc[2*i]])
+		b.WriteString(d.redactValue(keyPattern(pattern, k), c, name[loc[2*i]:loc[2*i+1]]))
+		at = loc[2*i+1]
+		k++
+	}
+	if at == 0 {
+		return name
+	}
+	b.WriteString(name[at:])
+	return b.String()
+}
+
+// checkKey adds error for key of map described by mk if key can't be
+// converted to type of map key or isn't allowed by mk.
+// Returns true if key is ok.
+func (d *StrictDecoder) checkKey(errs *Errs, pattern string, c *constraint, mk *mapKey, name, key string) bool {
+	reason := checkKey(mk.typ, key)
+	if reason == "" {
+		if mk.allowKey(key) {
+			return true
+		}
+	}
+	key = d.redactValue(pattern, c, key)
+	if reason == "" {
+		errs.addError(&FieldError{
+			Pattern: pattern,
+			Code:    "key not allowed",
+			Key:     name,
+			Value:   key,
+			Index:   -1,
+		})
+		return false
+	}
+	errs.addError(&FieldError{
+		Pattern: pattern,
+		Code:    "wrong key type",
+		Key:     name,
+		Value:   key,
+		Index:   -1,
+		Kind:    mk.typ.Kind(),
+		Bits:    valueBits(mk.typ),
+		Reason:  reason,
+	})
+	return false
+}
+
+// registerTextKeys teach form.Decoder to decode map keys of all types
+// implementing encoding.TextUnmarshaler used in struct typ.
+//
+// It's called before first decoding of typ (not on first key of such
+// type in values) to make decoding of same values independent of
+// previously decoded values.
+func (d *StrictDecoder) registerTextKeys(typ reflect.Type) {
+	d.mu.RLock()
+	registered := d.textKeysOf[typ]
+	d.mu.RUnlock()
+	if registered {
+		return
+	}
+	params := paramsForStruct(d.decoderOpts, typ)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.textKeysOf == nil {
+		d.textKeysOf = make(map[reflect.Type]bool)
+		d.textKeys = make(map[reflect.Type]bool)
+	}
+	d.textKeysOf[typ] = true
+	for _, c := range params {
+		for _, mk := range c.keys {
+			if !isTextKey(mk.typ) || d.textKeys[mk.typ] {
+				continue
+			}
+			d.textKeys[mk.typ] = true
+			keyType := mk.typ
+			d.decoder.RegisterCustomTypeFunc(func(vals []string) (interface{}, error) {
+				key := reflect.New(keyType)
+				err := key.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(vals[0]))
+				return key.Elem().Interface(), err
+			}, reflect.Zero(keyType).Interface())
+		}
+	}
+}
+
+// keyPattern returns part of pattern up to and including n-th [key].
+func keyPattern(pattern string, n int) string {
+	pos := 0
+	for ; n >= 0; n-- {
+		pos += strings.Index(pattern[pos:], "[key]") + len("[key]")
+	}
+	return pattern[:pos]
 }
 
 //nolint:gochecknoglobals
@@ -311,6 +1398,9 @@ var (
 	patternCache   = make(map[string]*regexp.Regexp)
 )
 
+// compilePattern returns regexp matching url.Values keys for pattern.
+//
+// Regexp has submatch named "idx" for each [idx] and "key" for each [key].
 func compilePattern(pattern string) *regexp.Regexp {
 	patternCacheMu.RLock()
 	re := patternCache[pattern]
@@ -327,9 +1417,9 @@ func compilePattern(pattern string) *regexp.Regexp {
 	for _, token := range rePatternToken.FindAllStringSubmatch(pattern, -1) {
 		switch token[0] {
 		case "[key]":
-			_, _ = b.WriteString(`\[[^\]]+\]`)
+			_, _ = b.WriteString(`\[(?P<key>[^\]]+)\]`)
 		case "[idx]":
-			_, _ = b.WriteString(`\[(\d+)\]`)
+			_, _ = b.WriteString(`\[(?P<idx>\d+)\]`)
 		default:
 			_, _ = b.WriteString(regexp.QuoteMeta(token[0]))
 		}
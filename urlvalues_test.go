@@ -1,10 +1,14 @@
 package urlvalues
 
 import (
+	"errors"
 	"net/url"
+	"reflect"
 	"sort"
+	"strings"
 	"testing"
 
+	"github.com/go-playground/form"
 	"github.com/powerman/check"
 )
 
@@ -32,6 +36,61 @@ func TestBadTagForm(tt *testing.T) {
 	t.PanicMatch(func() { _ = d.Decode(&v2, url.Values{"s": {""}}) }, `"wrong" .* "S"`)
 }
 
+func TestTagOptions(tt *testing.T) {
+	t := check.T(tt)
+	var data struct {
+		S string            `form:"s,omitempty,required"`
+		M map[string]string `form:"m,keys=a|b,omitempty"`
+	}
+	d := NewStrictDecoder()
+	t.Nil(d.Decode(&data, url.Values{"s": {"one"}, "m[a]": {"two"}}))
+	t.Equal(data.S, "one")
+	t.DeepEqual(data.M, map[string]string{"a": "two"})
+}
+
+func TestTagNames(tt *testing.T) {
+	t := check.T(tt)
+	type Data struct {
+		A int    `json:"a"`
+		B string `json:"b,omitempty,string" form:"bb,required"`
+		C int    `json:"-"`
+		D int    `json:",omitempty"`
+		E int    `json:"e" form:"-"`
+		F int    `json:"f,wrong" xml:"ff"`
+		G int    `json:",required"`
+		H int
+		I int `json:"i" form:",required"`
+		J int `json:"-,"`
+		K int `json:"-" form:",required"`
+	}
+	var data Data
+	d := NewStrictDecoder(TagNames("form", "json"))
+	t.Nil(d.Decode(&data, url.Values{"a": {"1"}, "bb": {"2"}, "D": {"3"}, "f": {"4"}, "G": {"5"}, "H": {"6"}, "i": {"7"}, "-": {"8"}, "K": {"9"}}))
+	t.DeepEqual(data, Data{A: 1, B: "2", D: 3, F: 4, G: 5, H: 6, I: 7, J: 8, K: 9})
+	errs := d.Decode(&data, url.Values{"b": {"2"}, "C": {"3"}, "e": {"4"}, "E": {"5"}})
+	sort.Strings(errs.(Errs).Values["-"])
+	t.DeepEqual(errs.(Errs).Values, url.Values{
+		"-":  {"C", "E", "b", "e"},
+		"G":  {"required"},
+		"bb": {"required"},
+		"i":  {"required"},
+		"K":  {"required"},
+	})
+
+	d = NewStrictDecoder(TagNames("form", "xml", "json"), Mode(form.ModeExplicit))
+	data = Data{}
+	t.Nil(d.Decode(&data, url.Values{"a": {"1"}, "bb": {"2"}, "ff": {"4"}, "G": {"5"}, "i": {"7"}, "K": {"9"}}))
+	t.DeepEqual(data, Data{A: 1, B: "2", F: 4, G: 5, I: 7, K: 9})
+	errs = d.Decode(&data, url.Values{"bb": {"2"}, "G": {"5"}, "H": {"6"}, "i": {"7"}, "K": {"9"}})
+	t.DeepEqual(errs.(Errs).Values, url.Values{"-": {"H"}})
+
+	var bad struct {
+		S string `json:"s" form:",wrong"`
+	}
+	t.PanicMatch(func() { _ = d.Decode(&bad, url.Values{}) }, `"wrong" .* "S"`)
+	t.PanicMatch(func() { TagNames() }, `at least one`)
+}
+
 func TestEmpty(tt *testing.T) {
 	t := check.T(tt)
 	var v1 struct{}
@@ -48,14 +107,275 @@ func TestSmoke(tt *testing.T) {
 		I int `form:"i"`
 	}{I: 42}
 	d := NewStrictDecoder()
-	t.DeepEqual(d.Decode(&v, url.Values{"i": {"bad"}}), Errs{url.Values{
+	errs := d.Decode(&v, url.Values{"i": {"bad"}})
+	t.DeepEqual(errs.(Errs).Values, url.Values{
 		"i": {"wrong type"},
-	}})
+	})
 	t.Equal(v.I, 42)
 	t.Nil(d.Decode(&v, url.Values{"i": {"10"}}))
 	t.Equal(v.I, 10)
 }
 
+func TestWrongType(tt *testing.T) {
+	t := check.T(tt)
+	var data struct {
+		I8 int8
+		U  uint
+		F  float32
+		B  bool
+		S  string
+		SU []uint16
+		MI map[string]int
+	}
+	d := NewStrictDecoder()
+	t.Nil(d.Decode(&data, url.Values{
+		"I8":    {"-128"},
+		"U":     {""},
+		"F":     {"1e38"},
+		"B":     {"on"},
+		"S":     {"any"},
+		"SU":    {"65535", ""},
+		"MI[a]": {"-42"},
+	}))
+	errs := d.Decode(&data, url.Values{
+		"I8":    {"128"},
+		"U":     {"-1"},
+		"F":     {"1e39"},
+		"B":     {"maybe"},
+		"SU[3]": {"x"},
+		"MI[a]": {"1.5"},
+	}).(Errs)
+	t.DeepEqual(errs.Values, url.Values{
+		"I8":      {"wrong type"},
+		"U":       {"wrong type"},
+		"F":       {"wrong type"},
+		"B":       {"wrong type"},
+		"SU[idx]": {"wrong type"},
+		"MI[key]": {"wrong type"},
+	})
+	t.DeepEqual(errs.Details("I8"), []*FieldError{{
+		Pattern: "I8", Code: "wrong type", Key: "I8", Value: "128", Index: -1,
+		Kind: reflect.Int8, Bits: 8, Reason: "overflow",
+	}})
+	t.DeepEqual(errs.Details("U"), []*FieldError{{
+		Pattern: "U", Code: "wrong type", Key: "U", Value: "-1", Index: -1,
+		Kind: reflect.Uint, Bits: 64, Reason: "sign",
+	}})
+	t.DeepEqual(errs.Details("F"), []*FieldError{{
+		Pattern: "F", Code: "wrong type", Key: "F", Value: "1e39", Index: -1,
+		Kind: reflect.Float32, Bits: 32, Reason: "overflow",
+	}})
+	t.DeepEqual(errs.Details("B"), []*FieldError{{
+		Pattern: "B", Code: "wrong type", Key: "B", Value: "maybe", Index: -1,
+		Kind: reflect.Bool, Reason: "syntax",
+	}})
+	t.DeepEqual(errs.Details("SU[idx]"), []*FieldError{{
+		Pattern: "SU[idx]", Code: "wrong type", Key: "SU[3]", Value: "x", Index: 3,
+		Kind: reflect.Uint16, Bits: 16, Reason: "syntax",
+	}})
+	t.DeepEqual(errs.Details("MI[key]"), []*FieldError{{
+		Pattern: "MI[key]", Code: "wrong type", Key: "MI[a]", Value: "1.5", Index: -1,
+		Kind: reflect.Int, Bits: 64, Reason: "syntax",
+	}})
+	t.Equal(errs.Details("SU[idx]")[0].Error(), `SU[idx]: wrong type (want uint16, got "x" at index 3: syntax)`)
+
+	errs = d.Decode(&data, url.Values{
+		"SU": {"1", "-2", "3", "70000"},
+	}).(Errs)
+	t.DeepEqual(errs.Values, url.Values{
+		"SU": {"wrong type", "wrong type"},
+	})
+	t.DeepEqual(errs.Details("SU"), []*FieldError{{
+		Pattern: "SU", Code: "wrong type", Key: "SU", Value: "-2", Index: 1,
+		Kind: reflect.Uint16, Bits: 16, Reason: "sign",
+	}, {
+		Pattern: "SU", Code: "wrong type", Key: "SU", Value: "70000", Index: 3,
+		Kind: reflect.Uint16, Bits: 16, Reason: "overflow",
+	}})
+
+	d = NewStrictDecoder(Redact(func(pattern, value string) string { return pattern + "=***" }))
+	errs = d.Decode(&data, url.Values{"I8": {"secret"}}).(Errs)
+	t.Equal(errs.Details("I8")[0].Value, "I8=***")
+	t.Equal(errs.Details("I8")[0].Error(), `I8: wrong type (want int8, got "I8=***": syntax)`)
+}
+
+func TestSecret(tt *testing.T) {
+	t := check.T(tt)
+	type Data struct {
+		Token string         `form:"token,secret,trim"`
+		PINs  []int          `form:"pin,secret"`
+		M     map[int]string `form:"m,secret"`
+		N     map[int]int    `form:"n"`
+		I     int
+	}
+	var data Data
+	d := NewStrictDecoder(Redact(func(pattern, value string) string { return "***" }))
+	report, err := d.DecodeWithReport(&data, url.Values{
+		"token": {" abc "},
+		"pin":   {"1", "x"},
+		"m[y]":  {"z"},
+		"n[1]":  {"z"},
+		"I":     {"i"},
+	})
+	errs := err.(Errs)
+	t.DeepEqual(errs.Details("pin"), []*FieldError{{
+		Pattern: "pin", Code: "wrong type", Key: "pin", Value: "[redacted]", Index: 1,
+		Kind: reflect.Int, Bits: 64, Reason: "syntax",
+	}})
+	t.Equal(errs.Details("m[key]")[0].Value, "[redacted]")
+	t.Equal(errs.Details("m[key]")[0].Key, "m[[redacted]]")
+	t.Equal(errs.Details("n[key]")[0].Key, "n[***]")
+	t.Equal(errs.Details("I")[0].Value, "***")
+	t.Equal(report.Warnings.Details("token")[0].Value, "[redacted]")
+
+	var secret []string
+	for _, p := range d.Params(Data{}) {
+		if p.Secret {
+			secret = append(secret, p.Pattern)
+		}
+	}
+	t.DeepEqual(secret, []string{"m[key]", "pin", "pin[idx]", "token"})
+
+	var bad struct {
+		S struct{ I int } `form:",secret"`
+	}
+	t.PanicMatch(func() { _ = d.Decode(&bad, url.Values{}) }, `"secret" require non-struct`)
+}
+
+type textKey struct{ a, b string }
+
+func (k *textKey) UnmarshalText(text []byte) error {
+	parts := strings.Split(string(text), ":")
+	if len(parts) != 2 {
+		return errors.New("want a:b")
+	}
+	k.a, k.b = parts[0], parts[1]
+	return nil
+}
+
+type uid [2]byte
+
+func (id *uid) UnmarshalText(text []byte) error {
+	if len(text) != len(id) {
+		return errors.New("want 2 bytes")
+	}
+	copy(id[:], text)
+	return nil
+}
+
+func TestTextKeyRegistration(tt *testing.T) {
+	t := check.T(tt)
+	var data struct {
+		M map[uid]int
+		V uid
+	}
+	d := NewStrictDecoder()
+	before := d.Decode(&data, url.Values{"V": {"1", "2"}})
+	t.Nil(d.Decode(&data, url.Values{"M[ab]": {"1"}}))
+	t.DeepEqual(d.Decode(&data, url.Values{"V": {"1", "2"}}), before)
+}
+
+func TestWrongKeyType(tt *testing.T) {
+	t := check.T(tt)
+	var data struct {
+		MI  map[int]string
+		MU  map[uint8]int
+		MB  map[bool]int
+		MT  map[textKey]int
+		MMI map[string]map[int]int
+		MSI map[int]struct{ I int }
+	}
+	d := NewStrictDecoder()
+	t.Nil(d.Decode(&data, url.Values{
+		"MI[-1]":      {"one"},
+		"MU[255]":     {"42"},
+		"MB[on]":      {"42"},
+		"MT[x:y]":     {"42"},
+		"MMI[a][10]":  {"42"},
+		"MSI[20].I":   {"42"},
+		"MSI[-20].I":  {"42"},
+		"MMI[b][-10]": {"42"},
+	}))
+	t.DeepEqual(data.MI, map[int]string{-1: "one"})
+	t.DeepEqual(data.MT, map[textKey]int{{a: "x", b: "y"}: 42})
+	t.DeepEqual(data.MMI, map[string]map[int]int{"a": {10: 42}, "b": {-10: 42}})
+	t.DeepEqual(data.MSI, map[int]struct{ I int }{20: {I: 42}, -20: {I: 42}})
+
+	errs := d.Decode(&data, url.Values{
+		"MI[abc]":    {"one"},
+		"MU[256]":    {"42"},
+		"MB[maybe]":  {"42"},
+		"MT[xy]":     {"42"},
+		"MMI[a][b]":  {"42"},
+		"MSI[1.5].I": {"42"},
+	}).(Errs)
+	t.DeepEqual(errs.Values, url.Values{
+		"MI[key]":       {"wrong key type"},
+		"MU[key]":       {"wrong key type"},
+		"MB[key]":       {"wrong key type"},
+		"MT[key]":       {"wrong key type"},
+		"MMI[key][key]": {"wrong key type"},
+		"MSI[key]":      {"wrong key type"},
+	})
+	t.DeepEqual(errs.Details("MI[key]"), []*FieldError{{
+		Pattern: "MI[key]", Code: "wrong key type", Key: "MI[abc]", Value: "abc", Index: -1,
+		Kind: reflect.Int, Bits: 64, Reason: "syntax",
+	}})
+	t.DeepEqual(errs.Details("MU[key]"), []*FieldError{{
+		Pattern: "MU[key]", Code: "wrong key type", Key: "MU[256]", Value: "256", Index: -1,
+		Kind: reflect.Uint8, Bits: 8, Reason: "overflow",
+	}})
+	t.DeepEqual(errs.Details("MT[key]"), []*FieldError{{
+		Pattern: "MT[key]", Code: "wrong key type", Key: "MT[xy]", Value: "xy", Index: -1,
+		Kind: reflect.Struct, Reason: "syntax",
+	}})
+	t.DeepEqual(errs.Details("MSI[key]"), []*FieldError{{
+		Pattern: "MSI[key]", Code: "wrong key type", Key: "MSI[1.5].I", Value: "1.5", Index: -1,
+		Kind: reflect.Int, Bits: 64, Reason: "syntax",
+	}})
+}
+
+func TestMapKeys(tt *testing.T) {
+	t := check.T(tt)
+	var data struct {
+		A  map[string]string             `form:"attrs,keys=a|b|c"`
+		P  map[string]struct{ I, J int } `form:"p,keypattern=^[a-z_]+$,maxkeys=2"`
+		SM []map[int]int                 `form:"sm,maxkeys=1"`
+	}
+	d := NewStrictDecoder()
+	t.Nil(d.Decode(&data, url.Values{
+		"attrs[a]":  {"1"},
+		"attrs[c]":  {"3"},
+		"p[x_y].I":  {"1"},
+		"p[x_y].J":  {"2"},
+		"p[z].J":    {"3"},
+		"sm[0][10]": {"1"},
+		"sm[1][20]": {"2"},
+	}))
+	t.DeepEqual(data.A, map[string]string{"a": "1", "c": "3"})
+
+	errs := d.Decode(&data, url.Values{
+		"attrs[a]":  {"1"},
+		"attrs[d]":  {"4"},
+		"p[x].I":    {"1"},
+		"p[y].I":    {"2"},
+		"p[z].J":    {"3"},
+		"p[Z].J":    {"3"},
+		"sm[0][10]": {"1"},
+		"sm[0][20]": {"2"},
+	}).(Errs)
+	sort.Strings(errs.Values["p[key]"])
+	t.DeepEqual(errs.Values, url.Values{
+		"attrs[key]":   {"key not allowed"},
+		"p[key]":       {"key not allowed", "too many keys"},
+		"sm[idx][key]": {"too many keys"},
+	})
+	t.DeepEqual(errs.Details("attrs[key]"), []*FieldError{{
+		Pattern: "attrs[key]", Code: "key not allowed", Key: "attrs[d]", Value: "d", Index: -1,
+	}})
+	t.Equal(errs.Details("attrs[key]")[0].Error(), `attrs[key]: key not allowed (got "d")`)
+}
+
 func TestIndexOutOfBounds(tt *testing.T) {
 	t := check.T(tt)
 	var data struct {
@@ -71,27 +391,28 @@ func TestIndexOutOfBounds(tt *testing.T) {
 		"AI[1]":            {"42"},
 		"AF[0].I":          {"42"},
 		"SI[9999]":         {"42"},
-		"SF[0000].I":       {"42"},
+		"SF[0].I":          {"42"},
 		"SSI[9999][9999]":  {"42"},
 		"SSI[0][0]":        {"42"},
 		"ASAI[9][9999][1]": {"42"},
 		"ASAI[0][0][0]":    {"42"},
 	}))
 	t.DeepEqual(d.Decode(&data, url.Values{
-		"AI[2]":             {"42"},
-		"AF[2].I":           {"42"},
-		"SI[10000]":         {"42"},
-		"SF[10000].I":       {"42"},
-		"SSI[10000][0]":     {"42"},
-		"SSI[0][10000]":     {"42"},
-		"SSI[10000][10000]": {"42"},
-		"ASAI[10][42][0]":   {"42"},
-		"ASAI[9][10000][0]": {"42"},
-		"ASAI[9][42][2]":    {"42"},
-	}), Errs{url.Values{
+		"AI[2]":                   {"42"},
+		"AF[2].I":                 {"42"},
+		"SI[10000]":               {"42"},
+		"SF[10000].I":             {"42"},
+		"SSI[10000][0]":           {"42"},
+		"SSI[0][10000]":           {"42"},
+		"SSI[10000][10000]":       {"42"},
+		"ASAI[10][42][0]":         {"42"},
+		"ASAI[9][10000][0]":       {"42"},
+		"ASAI[9][42][2]":          {"42"},
+		"SI[9223372036854775808]": {"42"},
+	}).(Errs).Values, url.Values{
 		"AI[idx]":   {"index out-of-bounds"},
 		"AF[idx].I": {"index out-of-bounds"},
-		"SI[idx]":   {"index out-of-bounds"},
+		"SI[idx]":   {"index out-of-bounds", "index out-of-bounds"},
 		"SF[idx].I": {"index out-of-bounds"},
 		"SSI[idx][idx]": {
 			"index out-of-bounds",
@@ -104,7 +425,39 @@ func TestIndexOutOfBounds(tt *testing.T) {
 			"index out-of-bounds",
 			"index out-of-bounds",
 		},
-	}})
+	})
+}
+
+func TestNonCanonicalIndex(tt *testing.T) {
+	t := check.T(tt)
+	var data struct {
+		SI  []int
+		SF  []struct{ I int }
+		MSI map[string][]int
+	}
+	d := NewStrictDecoder()
+	errs := d.Decode(&data, url.Values{
+		"SI[01]":       {"42"},
+		"SF[0000].I":   {"42"},
+		"MSI[a][00]":   {"42"},
+		"MSI[b][1]":    {"42"},
+		"MSI[b][01]":   {"42"},
+		"MSI[b][001]":  {"42"},
+		"MSI[c][0010]": {"42"},
+	})
+	sort.Strings(errs.(Errs).Values["MSI[key][idx]"])
+	t.DeepEqual(errs.(Errs).Values, url.Values{
+		"SI[idx]":   {"non-canonical index"},
+		"SF[idx].I": {"non-canonical index"},
+		"MSI[key][idx]": {
+			"multiple values",
+			"multiple values",
+			"non-canonical index",
+			"non-canonical index",
+			"non-canonical index",
+			"non-canonical index",
+		},
+	})
 }
 
 func TestMultipleValues(tt *testing.T) {
@@ -136,12 +489,61 @@ func TestMultipleValues(tt *testing.T) {
 		"I":       {"42", "43"},
 		"MI[a]":   {"10", "20"},
 		"MI[b]":   {"10", "20"},
-	}), Errs{url.Values{
+	}).(Errs).Values, url.Values{
 		"S2[idx]":   {"multiple values", "multiple values"},
 		"SF[idx].I": {"multiple values"},
 		"I":         {"multiple values"},
 		"MI[key]":   {"multiple values", "multiple values"},
-	}})
+	})
+}
+
+func TestDuplicatePolicy(tt *testing.T) {
+	t := check.T(tt)
+	type Data struct {
+		Agree bool           `form:"agree,last"`
+		I     int            `form:",first"`
+		S     []int          `form:",last"`
+		M     map[string]int `form:",last"`
+		J     int
+	}
+	var data Data
+	d := NewStrictDecoder()
+	t.Nil(d.Decode(&data, url.Values{
+		"agree": {"0", "1"},
+		"I":     {"10", "x"},
+		"S[0]":  {"x", "20"},
+		"M[a]":  {"x", "30"},
+		"J":     {"40"},
+	}))
+	t.DeepEqual(data, Data{
+		Agree: true,
+		I:     10,
+		S:     []int{20},
+		M:     map[string]int{"a": 30},
+		J:     40,
+	})
+	errs := d.Decode(&data, url.Values{
+		"agree": {"1", "x"},
+		"J":     {"50", "60"},
+	})
+	t.DeepEqual(errs.(Errs).Values, url.Values{
+		"agree": {"wrong type"},
+		"J":     {"multiple values"},
+	})
+
+	data = Data{}
+	d = NewStrictDecoder(DuplicatePolicy(DupLast))
+	t.Nil(d.Decode(&data, url.Values{
+		"I": {"10", "20"},
+		"J": {"30", "40"},
+	}))
+	t.Equal(data.I, 10)
+	t.Equal(data.J, 40)
+
+	var bad struct {
+		S struct{ I int } `form:",first"`
+	}
+	t.PanicMatch(func() { _ = d.Decode(&bad, url.Values{}) }, `"first" require non-struct`)
 }
 
 func TestTooManyValues(tt *testing.T) {
@@ -159,10 +561,10 @@ func TestTooManyValues(tt *testing.T) {
 		"SAI[0]":  {"10", "20", "30"},
 		"SAI[42]": {"10", "20", "30"},
 		"AI":      {"10", "20", "30"},
-	}), Errs{url.Values{
+	}).(Errs).Values, url.Values{
 		"SAI[idx]": {"too many values", "too many values"},
 		"AI":       {"too many values"},
-	}})
+	})
 }
 
 func TestMultipleNames(tt *testing.T) {
@@ -205,12 +607,167 @@ func TestMultipleNames(tt *testing.T) {
 		"Embed.I":    {"20"},
 		"S":          {"100", "200"},
 		"Embed.S[3]": {"400"},
-	}), Errs{url.Values{
+	}), Errs{Values: url.Values{
 		"I": {"multiple names for same value"},
 		"S": {"multiple names for same value"},
 	}})
 }
 
+func TestAltNames(tt *testing.T) {
+	t := check.T(tt)
+	type Filter struct {
+		Name string `form:"name,deprecated=n"`
+		Age  int
+	}
+	type Data struct {
+		Query  string   `form:"query,alias=q|search"`
+		IDs    []int    `form:"ids,deprecated=id"`
+		Filter *Filter  `form:"filter,alias=f"`
+		Tags   []string `form:"tags,required,deprecated=t"`
+	}
+	var warns []Errs
+	d := NewStrictDecoder(ReportWarnings(func(errs Errs) { warns = append(warns, errs) }))
+	var data Data
+	t.Nil(d.Decode(&data, url.Values{
+		"q":     {"one"},
+		"id":    {"1", "2"},
+		"f.n":   {"Alex"},
+		"f.Age": {"20"},
+		"t[0]":  {"x"},
+	}))
+	t.DeepEqual(data, Data{
+		Query:  "one",
+		IDs:    []int{1, 2},
+		Filter: &Filter{Name: "Alex", Age: 20},
+		Tags:   []string{"x"},
+	})
+	if t.Len(warns, 1) {
+		t.DeepEqual(warns[0].Values, url.Values{
+			"id":     {"deprecated"},
+			"f.n":    {"deprecated"},
+			"t[idx]": {"deprecated"},
+		})
+		t.DeepEqual(warns[0].Details("t[idx]"), []*FieldError{{
+			Pattern:     "t[idx]",
+			Code:        "deprecated",
+			Key:         "t[0]",
+			Index:       -1,
+			Reason:      "deprecated=t",
+			Suggestions: []string{"tags[idx]"},
+		}})
+	}
+
+	warns = nil
+	data = Data{}
+	errs := d.Decode(&data, url.Values{
+		"q":          {"a"},
+		"query":      {"b"},
+		"search":     {"c"},
+		"filter.Age": {"1"},
+		"f.Age":      {"2"},
+		"t":          {"x"},
+		"Q":          {"d"},
+		"T":          {"e"},
+	})
+	sort.Strings(errs.(Errs).Values["-"])
+	t.DeepEqual(errs.(Errs).Values, url.Values{
+		"-":          {"Q", "T"},
+		"query":      {"multiple names for same value", "multiple names for same value"},
+		"filter.Age": {"multiple names for same value"},
+	})
+	t.Len(warns, 1)
+	for _, err := range errs.(Errs).Details("-") {
+		switch err.Key {
+		case "Q":
+			t.DeepEqual(err.Suggestions, []string{"q"})
+		case "T":
+			t.Nil(err.Suggestions)
+		}
+	}
+
+	d = NewStrictDecoder(ShadowMode(func(Errs) {}))
+	data = Data{}
+	t.Nil(d.Decode(&data, url.Values{"query": {"a"}, "q": {"b"}, "f.Age": {"1"}, "tags": {"x"}}))
+	t.DeepEqual(data, Data{Query: "a", Filter: &Filter{Age: 1}, Tags: []string{"x"}})
+
+	params := make(map[string]Param)
+	for _, p := range NewStrictDecoder().Params(Data{}) {
+		params[p.Pattern] = p
+	}
+	t.Equal(params["query"].Alias, "query")
+	t.Equal(params["query"].Primary, "")
+	t.Equal(params["search"].Alias, "query")
+	t.Equal(params["search"].Primary, "query")
+	t.False(params["search"].Deprecated)
+	t.Equal(params["f.n"].Primary, "filter.name")
+	t.True(params["f.n"].Deprecated)
+	t.Equal(params["filter.n"].Primary, "filter.name")
+	t.Equal(params["t[idx]"].Primary, "tags[idx]")
+
+	var bad1 struct {
+		S string `form:"s,alias=a.b"`
+	}
+	var bad2 struct {
+		V url.Values `form:"v,remain,alias=a"`
+	}
+	t.PanicMatch(func() { _ = d.Decode(&bad1, url.Values{}) }, `bad tag option "alias=a.b"`)
+	t.PanicMatch(func() { _ = d.Decode(&bad2, url.Values{}) }, `"alias=a" not allowed for remain`)
+	var bad3 struct {
+		ID   string `form:"id"`
+		Slug string `form:"slug,deprecated=id"`
+	}
+	var bad4 struct {
+		Slug string `form:"slug,alias=id"`
+		ID   string `form:"id"`
+	}
+	t.PanicMatch(func() { _ = d.Decode(&bad3, url.Values{}) }, `tag option "deprecated=id" conflicts with param "id"`)
+	t.PanicMatch(func() { _ = d.Decode(&bad4, url.Values{}) }, `tag option "alias=id" conflicts with param "id"`)
+}
+
+func TestRejectMixedLists(tt *testing.T) {
+	t := check.T(tt)
+	var data struct {
+		A  [5]int
+		S  []int
+		SS [][]int
+		SF []struct{ S []int }
+	}
+	values := url.Values{
+		"A":          {"10", "20"},
+		"S[0]":       {"10"},
+		"SS[0]":      {"10", "20"},
+		"SS[1][0]":   {"30"},
+		"SF[0].S":    {"10"},
+		"SF[1].S[0]": {"20"},
+	}
+	d := NewStrictDecoder(RejectMixedLists())
+	t.Nil(d.Decode(&data, values))
+	t.Nil(NewStrictDecoder().Decode(&data, url.Values{
+		"A":    {"10", "20"},
+		"A[4]": {"40"},
+	}))
+	t.DeepEqual(data.A, [5]int{10, 20, 0, 0, 40})
+	errs := d.Decode(&data, url.Values{
+		"A":          {"10", "20"},
+		"A[4]":       {"40"},
+		"S":          {"10"},
+		"S[1]":       {"20"},
+		"S[2]":       {"30"},
+		"SS[0]":      {"10", "20"},
+		"SS[0][1]":   {"30"},
+		"SF[0].S":    {"10"},
+		"SF[0].S[1]": {"20"},
+		"SF[1].S":    {"10"},
+		"SF[1].S[1]": {"20"},
+	})
+	t.DeepEqual(errs, Errs{Values: url.Values{
+		"A":         {"mixed list notation"},
+		"S":         {"mixed list notation"},
+		"SS[idx]":   {"mixed list notation"},
+		"SF[idx].S": {"mixed list notation", "mixed list notation"},
+	}})
+}
+
 func TestRequired(tt *testing.T) {
 	t := check.T(tt)
 	var data struct {
@@ -222,12 +779,250 @@ func TestRequired(tt *testing.T) {
 		"i": {"0"},
 		"S": {""},
 	}))
-	t.DeepEqual(d.Decode(&data, url.Values{}), Errs{url.Values{
+	t.DeepEqual(d.Decode(&data, url.Values{}), Errs{Values: url.Values{
 		"i": {"required"},
 		"S": {"required"},
 	}})
 }
 
+func TestEmptyAsAbsent(tt *testing.T) {
+	t := check.T(tt)
+	type Data struct {
+		Name string  `form:"name,required,notempty"`
+		Age  *int    `form:"age,notempty"`
+		Nick *string `form:"nick,notempty"`
+		Note *string `form:"note"`
+		S    []int   `form:"s,notempty"`
+		M    map[string]string
+	}
+	var data Data
+	d := NewStrictDecoder()
+	t.Nil(d.Decode(&data, url.Values{
+		"name": {"", "Alex"},
+		"age":  {""},
+		"nick": {""},
+		"note": {""},
+		"s":    {"", ""},
+		"M[a]": {""},
+	}))
+	t.Equal(data.Name, "Alex")
+	t.Nil(data.Age)
+	t.Nil(data.Nick)
+	t.NotNil(data.Note)
+	t.Nil(data.S)
+	t.DeepEqual(data.M, map[string]string{"a": ""})
+	errs := d.Decode(&data, url.Values{
+		"name": {""},
+		"s":    {"1", "", "3"},
+	})
+	t.DeepEqual(errs.(Errs).Values, url.Values{
+		"name": {"required"},
+		"s":    {"wrong type"},
+	})
+	t.Equal(errs.(Errs).Details("s")[0].Error(), `s: wrong type (want int64, got "" at index 1: syntax)`)
+
+	data = Data{}
+	d = NewStrictDecoder(EmptyAsAbsent())
+	t.Nil(d.Decode(&data, url.Values{
+		"name": {"Alex"},
+		"note": {""},
+		"M[a]": {""},
+		"M[b]": {"B"},
+	}))
+	t.Nil(data.Note)
+	t.DeepEqual(data.M, map[string]string{"b": "B"})
+}
+
+func TestNormalize(tt *testing.T) {
+	t := check.T(tt)
+	type Data struct {
+		Email string            `form:"email,trim,lower,required,notempty"`
+		Code  string            `form:"code,upper"`
+		Q     string            `form:"q,collapse"`
+		N     int               `form:"n"`
+		Tags  []string          `form:"tag,trim,lower"`
+		M     map[string]string `form:"m,trim"`
+	}
+	var data Data
+	d := NewStrictDecoder()
+	values := url.Values{
+		"email":  {" Alex@Example.COM "},
+		"code":   {"ab1"},
+		"q":      {"  many \t  spaces\n"},
+		"tag":    {" A", "b ", "C"},
+		"m[key]": {" v "},
+	}
+	t.Nil(d.Decode(&data, values))
+	t.DeepEqual(data, Data{
+		Email: "alex@example.com",
+		Code:  "AB1",
+		Q:     "many spaces",
+		Tags:  []string{"a", "b", "c"},
+		M:     map[string]string{"key": "v"},
+	})
+	t.DeepEqual(values["tag"], []string{" A", "b ", "C"})
+	t.DeepEqual(d.Decode(&data, url.Values{"email": {"  "}}), Errs{Values: url.Values{
+		"email": {"required"},
+	}})
+	t.NotNil(d.Decode(&data, url.Values{"email": {"a"}, "n": {" 42 "}}))
+
+	d = NewStrictDecoder(TrimSpace())
+	t.Nil(d.Decode(&data, url.Values{"email": {"a"}, "n": {" 42 "}}))
+	t.Equal(data.N, 42)
+
+	var bad struct {
+		S string `form:",lower,upper"`
+	}
+	t.PanicMatch(func() { _ = d.Decode(&bad, url.Values{}) }, `conflicting tag options "lower" and "upper" on field "S"`)
+}
+
+func TestRequiredGroup(tt *testing.T) {
+	t := check.T(tt)
+	type Address struct {
+		City   string `form:",required"`
+		Street string
+	}
+	type Item struct {
+		Name string `form:",required"`
+		Addr *Address
+	}
+	var data struct {
+		Addr  *Address
+		Home  *Address `form:",required"`
+		Items []Item
+	}
+	d := NewStrictDecoder()
+	t.Nil(d.Decode(&data, url.Values{"Home.City": {"Kyiv"}}))
+	t.Nil(data.Addr)
+	t.Nil(data.Items)
+	t.Nil(d.Decode(&data, url.Values{
+		"Home.City":          {"Kyiv"},
+		"Addr.City":          {"Lviv"},
+		"Items[0].Name":      {"one"},
+		"Items[1].Name":      {"two"},
+		"Items[1].Addr.City": {"Odesa"},
+	}))
+	t.Equal(data.Addr.City, "Lviv")
+	t.Len(data.Items, 2)
+	t.DeepEqual(d.Decode(&data, url.Values{
+		"Addr.Street":          {"Main"},
+		"Items[0].Addr.Street": {"Main"},
+	}), Errs{Values: url.Values{
+		"Addr.City":            {"required"},
+		"Home":                 {"required"},
+		"Items[idx].Name":      {"required"},
+		"Items[idx].Addr.City": {"required"},
+	}})
+
+	type Order struct {
+		ID   int
+		Ship Address `form:",required"`
+	}
+	var mixed struct {
+		Addrs  []Address
+		M      map[string]Address
+		Items  []Item
+		Orders []Order
+	}
+	t.Nil(d.Decode(&mixed, url.Values{
+		"Addrs[0].City":       {"Kyiv"},
+		"Addrs[1].City":       {"Lviv"},
+		"M[a].City":           {"Kyiv"},
+		"Items[0].Name":       {"one"},
+		"Orders[0].Ship.City": {"Kyiv"},
+	}))
+	t.DeepEqual(d.Decode(&mixed, url.Values{
+		"Addrs[0].Street":     {"Main"},
+		"Addrs[1].City":       {"Lviv"},
+		"M[a].Street":         {"Main"},
+		"M[b].City":           {"Lviv"},
+		"Items[0].Name":       {"one"},
+		"Items[1].Addr.City":  {"Odesa"},
+		"Orders[0].ID":        {"1"},
+		"Orders[1].Ship.City": {"Kyiv"},
+	}), Errs{Values: url.Values{
+		"Addrs[idx].City":       {"required"},
+		"M[key].City":           {"required"},
+		"Items[idx].Name":       {"required"},
+		"Orders[idx].Ship":      {"required"},
+		"Orders[idx].Ship.City": {"required"},
+	}})
+}
+
+func TestRequiredConditional(tt *testing.T) {
+	t := check.T(tt)
+	type Item struct {
+		A string `form:"a,required_with=b"`
+		B string `form:"b"`
+	}
+	var data struct {
+		Lat  float64 `form:"lat,required_with=lng"`
+		Lng  float64 `form:"lng,required_with=lat"`
+		ID   int     `form:"id,required_without=slug,excluded_with=slug"`
+		Slug string  `form:"slug,required_without=id,excluded_with=id"`
+		S    []Item
+	}
+	d := NewStrictDecoder()
+	t.Nil(d.Decode(&data, url.Values{"id": {"1"}}))
+	t.Nil(d.Decode(&data, url.Values{"slug": {"a"}, "lat": {"1"}, "lng": {"2"}}))
+	t.Nil(d.Decode(&data, url.Values{"slug": {"a"}, "S[0].a": {"x"}, "S[0].b": {"y"}}))
+
+	errs := d.Decode(&data, url.Values{"lat": {"1"}, "S[0].b": {"y"}})
+	t.DeepEqual(errs.(Errs).Values, url.Values{
+		"lng":      {"required"},
+		"id":       {"required"},
+		"slug":     {"required"},
+		"S[idx].a": {"required"},
+	})
+	t.Equal(errs.(Errs).Details("lng")[0].Error(), "lng: required (required_with=lat)")
+	errs = d.Decode(&data, url.Values{"id": {"1"}, "slug": {"a"}})
+	t.DeepEqual(errs.(Errs).Values, url.Values{
+		"id":   {"excluded"},
+		"slug": {"excluded"},
+	})
+	t.Equal(errs.(Errs).Details("id")[0].Error(), "id: excluded (excluded_with=slug)")
+
+	type Node struct {
+		ID   string `form:"id,required_without=slug"`
+		Slug string `form:"slug,excluded_with=id"`
+		Name string `form:"name"`
+	}
+	var optional struct {
+		X     int
+		Inner *Node           `form:"inner"`
+		Items []Node          `form:"items"`
+		M     map[string]Node `form:"m"`
+	}
+	t.Nil(d.Decode(&optional, url.Values{"X": {"1"}}))
+	errs = d.Decode(&optional, url.Values{"inner.name": {"a"}, "items[0].name": {"b"}})
+	t.DeepEqual(errs.(Errs).Values, url.Values{
+		"inner.id":      {"required"},
+		"items[idx].id": {"required"},
+	})
+	t.Nil(d.Decode(&optional, url.Values{"items[0].id": {"1"}, "items[1].slug": {"a"}, "m[a].id": {"2"}, "m[b].slug": {"b"}}))
+	errs = d.Decode(&optional, url.Values{"items[0].id": {"1"}, "items[0].slug": {"a"}, "items[1].name": {"b"}, "m[a].name": {"c"}})
+	t.DeepEqual(errs.(Errs).Values, url.Values{
+		"items[idx].id":   {"required"},
+		"items[idx].slug": {"excluded"},
+		"m[key].id":       {"required"},
+	})
+
+	var bad1 struct {
+		A int `form:",required_with=B"`
+	}
+	var bad2 struct {
+		A struct{ I int } `form:",excluded_with=B"`
+		B int
+	}
+	var bad3 struct {
+		A int `form:",required_with=B"`
+		B map[string]int
+	}
+	t.PanicMatch(func() { _ = d.Decode(&bad1, url.Values{}) }, `unknown non-struct non-map field "B" in tag option "required_with=B"`)
+	t.PanicMatch(func() { _ = d.Decode(&bad3, url.Values{}) }, `unknown non-struct non-map field "B" in tag option "required_with=B"`)
+	t.PanicMatch(func() { _ = d.Decode(&bad2, url.Values{}) }, `"excluded_with=B" require non-struct`)
+}
+
 func TestUnknown(tt *testing.T) {
 	t := check.T(tt)
 	var data struct {
@@ -242,28 +1037,28 @@ func TestUnknown(tt *testing.T) {
 	t.DeepEqual(d.Decode(&data, url.Values{
 		"A": {"one"},
 		"S": {"one", "two"},
-	}), Errs{url.Values{
+	}).(Errs).Values, url.Values{
 		"-": {"A"},
 		"S": {"multiple values"},
-	}})
+	})
 	t.DeepEqual(d.Decode(&data, url.Values{
 		"A": {"one"},
-	}), Errs{url.Values{
+	}).(Errs).Values, url.Values{
 		"-": {"A"},
-	}})
+	})
 	t.DeepEqual(d.Decode(&data, url.Values{
 		"M": {"one"},
-	}), Errs{url.Values{
+	}).(Errs).Values, url.Values{
 		"-": {"M"},
-	}})
+	})
 	errs := d.Decode(&data, url.Values{
 		"A": {"one"},
 		"F": {"42"},
 	})
 	sort.Strings(errs.(Errs).Values["-"])
-	t.DeepEqual(errs, Errs{url.Values{
+	t.DeepEqual(errs.(Errs).Values, url.Values{
 		"-": {"A", "F"},
-	}})
+	})
 
 	d = NewStrictDecoder(IgnoreUnknown())
 	t.Nil(d.Decode(&data, url.Values{
@@ -271,6 +1066,229 @@ func TestUnknown(tt *testing.T) {
 	}))
 }
 
+func TestRejectInvalidText(tt *testing.T) {
+	t := check.T(tt)
+	var data struct {
+		S  string
+		SS []string
+		M  map[string]string
+	}
+	values := url.Values{
+		"S":       {"a\x00b"},
+		"SS":      {"ok", "a\tb", "\xc0"},
+		"M[\x1b]": {"x"},
+		"M[a]":    {"\u0085"},
+		"\xc0":    {"x"},
+		"unknown": {"x"},
+	}
+	t.Nil(NewStrictDecoder(IgnoreUnknown()).Decode(&data, url.Values{"S": {"a\x00b"}}))
+
+	d := NewStrictDecoder(RejectInvalidText("\t\r\n"))
+	t.Nil(d.Decode(&data, url.Values{"S": {"ok\r\n"}, "SS": {"\tПривіт"}}))
+	errs := d.Decode(&data, values)
+	sort.Strings(errs.(Errs).Values["-"])
+	t.DeepEqual(errs.(Errs).Values, url.Values{
+		"-":      {"M[\x1b]", "unknown", "\xc0"},
+		"S":      {"invalid text"},
+		"SS":     {"invalid text"},
+		"M[key]": {"invalid text"},
+	})
+	t.Equal(errs.(Errs).Details("S")[0].Error(), `S: invalid text (got "a\x00b": control)`)
+	t.Equal(errs.(Errs).Details("SS")[0].Error(), `SS: invalid text (got "\xc0": utf8)`)
+	t.Equal(errs.(Errs).Details("SS")[0].Index, 2)
+
+	d = NewStrictDecoder(RejectInvalidText(""), IgnoreUnknown())
+	errs = d.Decode(&data, values)
+	sort.Strings(errs.(Errs).Values["-"])
+	t.DeepEqual(errs.(Errs).Values["-"], []string{"M[\x1b]", "\xc0"})
+}
+
+func TestIgnoreUnknownMatching(tt *testing.T) {
+	t := check.T(tt)
+	var data struct {
+		S string
+	}
+	d := NewStrictDecoder(IgnoreUnknownMatching("utm_*", "fbclid"), IgnoreUnknownMatching("_"))
+	t.Nil(d.Decode(&data, url.Values{
+		"S":          {"one"},
+		"utm_source": {"x"},
+		"utm_[":      {"x"},
+		"fbclid":     {"x"},
+		"_":          {"x"},
+	}))
+	t.Equal(data.S, "one")
+	errs := d.Decode(&data, url.Values{
+		"S":        {"two"},
+		"utm_term": {"x"},
+		"utm":      {"x"},
+		"fbclid2":  {"x"},
+		"__":       {"x"},
+	})
+	sort.Strings(errs.(Errs).Values["-"])
+	t.DeepEqual(errs.(Errs).Values, url.Values{
+		"-": {"__", "fbclid2", "utm"},
+	})
+	t.Equal(data.S, "one")
+
+	t.PanicMatch(func() { IgnoreUnknownMatching("a[") }, `bad pattern "a\["`)
+}
+
+func TestRemain(tt *testing.T) {
+	t := check.T(tt)
+	type Filter struct {
+		Name  string
+		Extra map[string][]string `form:",remain"`
+	}
+	var data struct {
+		I      int
+		Filter *Filter
+		Rest   url.Values `form:"rest,remain"`
+	}
+	d := NewStrictDecoder()
+	t.Nil(d.Decode(&data, url.Values{"I": {"42"}}))
+	t.Nil(data.Filter)
+	t.Nil(data.Rest)
+	t.Nil(d.Decode(&data, url.Values{
+		"I":           {"42"},
+		"A":           {"one", "two"},
+		"B[":          {"three"},
+		"rest":        {"four"},
+		"Filter.Name": {"name"},
+		"Filter.a[x]": {"five"},
+		"Filter.b.c":  {"six"},
+		"Filterx":     {"seven"},
+	}))
+	t.Equal(data.I, 42)
+	t.DeepEqual(data.Rest, url.Values{
+		"A":       {"one", "two"},
+		"B[":      {"three"},
+		"rest":    {"four"},
+		"Filterx": {"seven"},
+	})
+	t.DeepEqual(data.Filter, &Filter{
+		Name: "name",
+		Extra: map[string][]string{
+			"a[x]": {"five"},
+			"b.c":  {"six"},
+		},
+	})
+
+	var bad1 struct {
+		A url.Values `form:",remain"`
+		B url.Values `form:",remain"`
+	}
+	var bad2 struct {
+		A map[string]string `form:",remain"`
+	}
+	var bad3 struct {
+		S []struct {
+			A url.Values `form:",remain"`
+		}
+	}
+	t.PanicMatch(func() { _ = d.Decode(&bad1, url.Values{}) }, `multiple remain fields "A" and "B"`)
+	t.PanicMatch(func() { _ = d.Decode(&bad2, url.Values{}) }, `remain field "A" must be url.Values`)
+	t.PanicMatch(func() { _ = d.Decode(&bad3, url.Values{}) }, `remain field "A" inside`)
+}
+
+func TestShadowMode(tt *testing.T) {
+	t := check.T(tt)
+	var data struct {
+		I int
+		B bool
+		A [2]int
+		S []int `form:"s"`
+		M map[int]string
+		R url.Values `form:",remain"`
+	}
+	var reported []Errs
+	d := NewStrictDecoder(MaxArraySize(3), ShadowMode(func(errs Errs) {
+		reported = append(reported, errs)
+	}))
+	t.Nil(d.Decode(&data, url.Values{"I": {"42"}}))
+	t.Len(reported, 0)
+
+	values := url.Values{
+		"I":    {"10", "20"},
+		"B":    {"maybe"},
+		"A":    {"1", "2", "3"},
+		"A[5]": {"5"},
+		"s":    {"1", "x", "3", "4"},
+		"M[1]": {"one"},
+		"M[x]": {"two"},
+		"B[":   {"three"},
+	}
+	t.Nil(d.Decode(&data, values))
+	t.DeepEqual(values["s"], []string{"1", "x", "3", "4"})
+	t.Equal(data.I, 10)
+	t.False(data.B)
+	t.DeepEqual(data.A, [2]int{1, 2})
+	t.DeepEqual(data.S, []int{1, 0, 3})
+	t.DeepEqual(data.M, map[int]string{1: "one"})
+	t.DeepEqual(data.R, url.Values{"B[": {"three"}})
+	if t.Len(reported, 1) {
+		t.DeepEqual(reported[0].Values, url.Values{
+			"I":      {"multiple values"},
+			"B":      {"wrong type"},
+			"A":      {"too many values"},
+			"A[idx]": {"index out-of-bounds"},
+			"s":      {"too many values", "wrong type"},
+			"M[key]": {"wrong key type"},
+		})
+	}
+}
+
+func TestDecodeWithReport(tt *testing.T) {
+	t := check.T(tt)
+	type Data struct {
+		Query string   `form:"q,deprecated=query"`
+		Tags  []string `form:"tags,lower"`
+		N     int
+	}
+	var data Data
+	d := NewStrictDecoder(TrimSpace(), IgnoreUnknownMatching("utm_*"),
+		Redact(func(pattern, value string) string { return "<" + value + ">" }))
+	report, err := d.DecodeWithReport(&data, url.Values{"N": {"1"}})
+	t.Nil(err)
+	t.Len(report.Warnings.Values, 0)
+
+	report, err = d.DecodeWithReport(&data, url.Values{
+		"query":      {" a "},
+		"tags":       {"A", "b"},
+		"utm_source": {"x"},
+		"N":          {"2"},
+	})
+	t.Nil(err)
+	t.DeepEqual(data, Data{Query: "a", Tags: []string{"a", "b"}, N: 2})
+	t.DeepEqual(report.Warnings.Values, url.Values{
+		"-":     {"utm_source"},
+		"query": {"normalized", "deprecated"},
+		"tags":  {"normalized"},
+	})
+	t.DeepEqual(report.Warnings.Details("tags"), []*FieldError{{
+		Pattern: "tags",
+		Code:    "normalized",
+		Key:     "tags",
+		Value:   "<A>",
+		Index:   0,
+	}})
+
+	report, err = d.DecodeWithReport(&data, url.Values{"N": {" 3"}, "utm_id": {"x"}, "bad": {"y"}})
+	t.DeepEqual(err.(Errs).Values, url.Values{"-": {"bad"}})
+	t.DeepEqual(report.Warnings.Values, url.Values{
+		"-": {"utm_id"},
+		"N": {"normalized"},
+	})
+
+	var warns []Errs
+	d = NewStrictDecoder(IgnoreUnknown(), ReportWarnings(func(errs Errs) { warns = append(warns, errs) }))
+	t.Nil(d.Decode(&data, url.Values{"N": {"4"}}))
+	t.Len(warns, 0)
+	t.Nil(d.Decode(&data, url.Values{"N": {"4"}, "x": {"y"}}))
+	if t.Len(warns, 1) {
+		t.DeepEqual(warns[0].Values, url.Values{"-": {"x"}})
+	}
+}
+
 func TestPartial(tt *testing.T) {
 	t := check.T(tt)
 	type Part struct {
@@ -293,9 +1311,9 @@ func TestPartial(tt *testing.T) {
 		"last.I":  {"399"},
 	})
 	sort.Strings(errs.(Errs).Values["-"])
-	t.DeepEqual(errs, Errs{url.Values{
+	t.DeepEqual(errs.(Errs).Values, url.Values{
 		"-": {"First.I", "last.I"},
-	}})
+	})
 	t.Nil(d.Decode(&v, url.Values{"I": {"200"}}))
 	t.DeepEqual(v, Data{First: Part{I: 100}, I: 200, last: Part{I: 30}})
 
@@ -458,3 +1476,34 @@ func BenchmarkLargeSuccessLoose(b *testing.B) {
 		}
 	}
 }
+
+func TestValidate(tt *testing.T) {
+	t := check.T(tt)
+	type Data struct {
+		I int      `form:"i,required"`
+		S []string `form:"s"`
+		R struct {
+			Rest url.Values `form:",remain"`
+		} `form:"r"`
+	}
+	values := url.Values{"i": {"x"}, "s[1]": {"a"}, "r.x": {"1"}, "utm_id": {"1"}, "z": {"1"}}
+	d := NewStrictDecoder(IgnoreUnknownMatching("utm_*"))
+	errs := d.Validate(Data{}, values)
+	t.DeepEqual(errs.(Errs).Values, url.Values{"i": {"wrong type"}, "-": {"z"}})
+	t.DeepEqual(errs, d.Decode(&Data{}, values))
+	t.Nil(d.Validate(reflect.TypeOf(Data{}), url.Values{"i": {"1"}, "r.x": {"1"}}))
+
+	var warns []Errs
+	d = NewStrictDecoder(IgnoreUnknown(), ShadowMode(func(Errs) {}),
+		ReportWarnings(func(errs Errs) { warns = append(warns, errs) }))
+	errs = d.Validate(&Data{}, values)
+	t.DeepEqual(errs.(Errs).Values, url.Values{"i": {"wrong type"}})
+	if t.Len(warns, 1) {
+		sort.Strings(warns[0].Values["-"])
+		t.DeepEqual(warns[0].Values, url.Values{"-": {"utm_id", "z"}})
+	}
+	t.Nil(d.Decode(&Data{}, values))
+
+	t.PanicMatch(func() { _ = d.Validate(Data{}, nil) }, `^data .* nil`)
+	t.PanicMatch(func() { _ = d.Validate(42, url.Values{}) }, `^v .* struct`)
+}